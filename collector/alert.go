@@ -0,0 +1,91 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// alertTotals is shared by the collectors, which are created on every
+	// scrape, and updated by the alert socket listener started with the
+	// first one.
+	alertTotals = newAlertTotals()
+	alertOnce   sync.Once
+	alertErr    error
+)
+
+type alertCollector struct {
+	alertEvents             *prometheus.Desc
+	alertNodeEvents         *prometheus.Desc
+	alertResourceOperations *prometheus.Desc
+	alertFencingEvents      *prometheus.Desc
+	alertTotals             *alertTotalsStruct
+}
+
+func init() {
+	registerCollector("alert", defaultDisabled, NewAlertCollector)
+}
+
+// NewAlertCollector returns a new Collector exposing the counters of the
+// Pacemaker alerts forwarded by the alert agent, and starts listening for
+// them on its first call.
+func NewAlertCollector() (Collector, error) {
+	alertOnce.Do(func() {
+		alertErr = listenAlerts(*alertSocketPath, alertTotals)
+	})
+
+	if alertErr != nil {
+		return nil, fmt.Errorf("couldn't listen for alerts: %s", alertErr)
+	}
+
+	return &alertCollector{
+		alertEvents: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "alert", "events_total"),
+			"Number of Pacemaker alerts received per kind, other for unknown kinds.",
+			[]string{"kind"}, nil,
+		),
+		alertNodeEvents: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "alert", "node_events_total"),
+			"Number of node membership alerts received per node and state.",
+			[]string{"node", "state"}, nil,
+		),
+		alertResourceOperations: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "alert", "resource_operations_total"),
+			"Number of resource operation alerts received per resource, task and return code.",
+			[]string{"rsc", "task", "rc"}, nil,
+		),
+		alertFencingEvents: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "alert", "fencing_events_total"),
+			"Number of fencing alerts received per target node, task and return code.",
+			[]string{"node", "task", "rc"}, nil,
+		),
+		alertTotals: alertTotals,
+	}, nil
+}
+
+// Update calls (*alertCollector).getAlertInfo to get the alert metrics.
+func (c *alertCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getAlertInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get alert information: %s", err)
+	}
+
+	return nil
+}
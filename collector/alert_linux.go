@@ -0,0 +1,264 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+const (
+	// alertEnvPrefix is the prefix of the environment variables Pacemaker
+	// sets for the alert agents.
+	alertEnvPrefix = "CRM_alert_"
+	// alertMaxSize bounds the size of a forwarded alert.
+	alertMaxSize = 64 * 1024
+	// alertTimeout bounds the time to forward an alert.
+	alertTimeout = 5 * time.Second
+	// alertGroup is the group Pacemaker runs the alert agents as.
+	alertGroup = "haclient"
+)
+
+// alertKinds are the kinds of alerts sent by Pacemaker, any other kind is
+// counted as "other".
+var alertKinds = []string{"node", "fencing", "resource", "attribute"}
+
+// alertResourceKey identifies a resource operation alerts counter.
+type alertResourceKey struct {
+	rsc  string
+	task string
+	rc   string
+}
+
+// alertFencingKey identifies a fencing alerts counter.
+type alertFencingKey struct {
+	node string
+	task string
+	rc   string
+}
+
+// alertNodeKey identifies a node alerts counter.
+type alertNodeKey struct {
+	node  string
+	state string
+}
+
+// alertTotalsStruct accumulates the counters of the alerts received since
+// the exporter started.
+type alertTotalsStruct struct {
+	sync.Mutex
+	events             map[string]float64
+	nodeEvents         map[alertNodeKey]float64
+	resourceOperations map[alertResourceKey]float64
+	fencingEvents      map[alertFencingKey]float64
+}
+
+// newAlertTotals returns empty alert counters.
+func newAlertTotals() *alertTotalsStruct {
+	return &alertTotalsStruct{
+		events:             make(map[string]float64),
+		nodeEvents:         make(map[alertNodeKey]float64),
+		resourceOperations: make(map[alertResourceKey]float64),
+		fencingEvents:      make(map[alertFencingKey]float64),
+	}
+}
+
+// update counts an alert, given as its CRM_alert_* variables.
+func (c *alertTotalsStruct) update(alert map[string]string) {
+	c.Lock()
+	defer c.Unlock()
+
+	kind := alert[alertEnvPrefix+"kind"]
+	if kind == "" {
+		return
+	}
+
+	if !stringInSlice(kind, alertKinds) {
+		kind = "other"
+	}
+
+	c.events[kind]++
+
+	switch kind {
+	case "node":
+		c.nodeEvents[alertNodeKey{alert[alertEnvPrefix+"node"],
+			alert[alertEnvPrefix+"desc"]}]++
+	case "resource":
+		c.resourceOperations[alertResourceKey{alert[alertEnvPrefix+"rsc"],
+			alert[alertEnvPrefix+"task"], alert[alertEnvPrefix+"rc"]}]++
+	case "fencing":
+		c.fencingEvents[alertFencingKey{alert[alertEnvPrefix+"node"],
+			alert[alertEnvPrefix+"task"], alert[alertEnvPrefix+"rc"]}]++
+	}
+}
+
+// alertEnv returns the CRM_alert_* variables of an environment.
+func alertEnv(environ []string) map[string]string {
+	alert := make(map[string]string)
+
+	for _, env := range environ {
+		keyValue := strings.SplitN(env, "=", 2)
+		if len(keyValue) == 2 && strings.HasPrefix(keyValue[0], alertEnvPrefix) {
+			alert[keyValue[0]] = keyValue[1]
+		}
+	}
+
+	return alert
+}
+
+// SendAlert forwards the alert of the CRM_alert_* variables of an
+// environment to the alert collector of the running exporter.
+func SendAlert(environ []string) error {
+	return sendAlert(*alertSocketPath, environ)
+}
+
+// sendAlert forwards an alert on the alert socket.
+func sendAlert(path string, environ []string) error {
+	alert := alertEnv(environ)
+	if alert[alertEnvPrefix+"kind"] == "" {
+		return fmt.Errorf("no %skind in the environment, not called by Pacemaker", alertEnvPrefix)
+	}
+
+	conn, err := net.DialTimeout("unix", path, alertTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(alertTimeout)); err != nil {
+		return err
+	}
+
+	return json.NewEncoder(conn).Encode(alert)
+}
+
+// receiveAlert counts the alert sent on a connection.
+func receiveAlert(conn net.Conn, totals *alertTotalsStruct) {
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(alertTimeout)); err != nil {
+		log.Errorln(err)
+		return
+	}
+
+	var received map[string]string
+
+	err := json.NewDecoder(io.LimitReader(conn, alertMaxSize)).Decode(&received)
+	if err != nil {
+		log.Errorf("couldn't decode alert: %s", err)
+		return
+	}
+
+	// Only keep the variables an alert agent receives.
+	alert := make(map[string]string)
+	for key, value := range received {
+		if strings.HasPrefix(key, alertEnvPrefix) {
+			alert[key] = value
+		}
+	}
+
+	totals.update(alert)
+}
+
+// listenAlerts creates the alert socket, writable by the haclient group the
+// alert agents run as, and counts the alerts received on it.
+func listenAlerts(path string, totals *alertTotalsStruct) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// Remove the socket left by a previous exporter.
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+
+	if group, err := user.LookupGroup(alertGroup); err == nil {
+		gid, _ := strconv.Atoi(group.Gid)
+		if err := os.Chown(path, -1, gid); err != nil {
+			log.Warnf("couldn't change the alert socket group to %s: %s", alertGroup, err)
+		}
+	}
+
+	if err := os.Chmod(path, 0660); err != nil {
+		listener.Close()
+		return err
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				log.Errorf("couldn't accept alert: %s", err)
+				time.Sleep(time.Second)
+				continue
+			}
+
+			go receiveAlert(conn, totals)
+		}
+	}()
+
+	return nil
+}
+
+// getAlertInfo returns the alert counters
+func (c *alertCollector) getAlertInfo(ch chan<- prometheus.Metric) error {
+	c.alertTotals.Lock()
+	defer c.alertTotals.Unlock()
+
+	c.exposeAlerts(ch, c.alertTotals)
+
+	return nil
+}
+
+// expose alert metrics
+func (c *alertCollector) exposeAlerts(ch chan<- prometheus.Metric, totals *alertTotalsStruct) {
+	for kind, value := range totals.events {
+		ch <- prometheus.MustNewConstMetric(c.alertEvents,
+			prometheus.CounterValue, value, kind)
+	}
+
+	for key, value := range totals.nodeEvents {
+		ch <- prometheus.MustNewConstMetric(c.alertNodeEvents,
+			prometheus.CounterValue, value, key.node, key.state)
+	}
+
+	for key, value := range totals.resourceOperations {
+		ch <- prometheus.MustNewConstMetric(c.alertResourceOperations,
+			prometheus.CounterValue, value, key.rsc, key.task, key.rc)
+	}
+
+	for key, value := range totals.fencingEvents {
+		ch <- prometheus.MustNewConstMetric(c.alertFencingEvents,
+			prometheus.CounterValue, value, key.node, key.task, key.rc)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testAlerts = [][]string{
+	{
+		"CRM_alert_kind=resource", "CRM_alert_node=lustre-mds1",
+		"CRM_alert_rsc=lustre-mgs", "CRM_alert_task=monitor",
+		"CRM_alert_interval=120000", "CRM_alert_rc=7", "CRM_alert_target_rc=0",
+		"CRM_alert_desc=not running", "PATH=/usr/sbin:/usr/bin",
+	},
+	{
+		"CRM_alert_kind=resource", "CRM_alert_node=lustre-mds1",
+		"CRM_alert_rsc=lustre-mgs", "CRM_alert_task=monitor",
+		"CRM_alert_interval=120000", "CRM_alert_rc=7", "CRM_alert_target_rc=0",
+		"CRM_alert_desc=not running",
+	},
+	{
+		"CRM_alert_kind=fencing", "CRM_alert_node=lustre-mds2",
+		"CRM_alert_task=reboot", "CRM_alert_rc=0",
+		"CRM_alert_desc=Operation reboot of lustre-mds2 by lustre-mds1 for pacemaker-controld.1880: OK",
+	},
+	{
+		"CRM_alert_kind=node", "CRM_alert_node=lustre-mds2",
+		"CRM_alert_nodeid=2", "CRM_alert_desc=lost",
+	},
+	{
+		"CRM_alert_kind=unknown-1", "CRM_alert_node=lustre-mds2",
+	},
+}
+
+func TestAlertTotals(t *testing.T) {
+	totals := newAlertTotals()
+
+	for _, environ := range testAlerts {
+		totals.update(alertEnv(environ))
+	}
+
+	if totals.events["resource"] != 2 || totals.events["fencing"] != 1 ||
+		totals.events["node"] != 1 || totals.events["other"] != 1 || len(totals.events) != 4 {
+		t.Fatalf("events: %v", totals.events)
+	}
+
+	if value := totals.resourceOperations[alertResourceKey{"lustre-mgs", "monitor", "7"}]; value != 2 {
+		t.Fatalf("resource operations lustre-mgs/monitor/7: %v!=2", value)
+	}
+
+	if value := totals.fencingEvents[alertFencingKey{"lustre-mds2", "reboot", "0"}]; value != 1 {
+		t.Fatalf("fencing events lustre-mds2/reboot/0: %v!=1", value)
+	}
+
+	if value := totals.nodeEvents[alertNodeKey{"lustre-mds2", "lost"}]; value != 1 {
+		t.Fatalf("node events lustre-mds2/lost: %v!=1", value)
+	}
+}
+
+func TestSendAlert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "alert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "alert.sock")
+	totals := newAlertTotals()
+
+	if err := listenAlerts(path, totals); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sendAlert(path, []string{"PATH=/usr/bin"}); err == nil {
+		t.Fatalf("alert without CRM_alert_kind: no error")
+	}
+
+	for _, environ := range testAlerts {
+		if err := sendAlert(path, environ); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The alerts are counted asynchronously.
+	for i := 0; i < 100; i++ {
+		totals.Lock()
+		var events float64
+		for _, value := range totals.events {
+			events += value
+		}
+		totals.Unlock()
+
+		if events == float64(len(testAlerts)) {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("alerts received: %v!=%v", totals.events, len(testAlerts))
+}
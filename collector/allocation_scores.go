@@ -0,0 +1,62 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	allocationScoresInfinity = kingpin.Flag("collector.allocation_scores.infinity",
+		"Value exported for an INFINITY allocation score, negated for -INFINITY.").Default("1000000").Float64()
+	allocationScoresResources = kingpin.Flag("collector.allocation_scores.resources",
+		"Comma separated resources whose allocation scores are exported, all when empty.").Default("").String()
+)
+
+type allocationScoresCollector struct {
+	crmSimulateQuerier
+
+	allocationScore *prometheus.Desc
+}
+
+func init() {
+	registerCollector("allocation_scores", defaultDisabled, NewAllocationScoresCollector)
+}
+
+// NewAllocationScoresCollector returns a new Collector exposing the scheduler allocation scores.
+func NewAllocationScoresCollector() (Collector, error) {
+	return &allocationScoresCollector{
+		allocationScore: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "resource", "allocation_score"),
+			"Allocation score of the resource on the node computed by the scheduler.",
+			[]string{"resource", "node"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*allocationScoresCollector).getAllocationScoresInfo to get
+// the allocation scores metrics.
+func (c *allocationScoresCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getAllocationScoresInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get allocation scores information: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// allocationScoreLine matches an allocation score of crm_simulate, which is
+// prefixed by the scheduler function, e.g. "pcmk__native_allocate: ".
+var allocationScoreLine = regexp.MustCompile(`^(?:\S+: )?(\S+) allocation score on (\S+): (\S+)$`)
+
+// allocationScoreKey identifies an allocation score.
+type allocationScoreKey struct {
+	Resource string
+	Node     string
+}
+
+// parseAllocationScores returns the allocation scores of crm_simulate. A
+// score printed more than once, by older versions, keeps its last value.
+func parseAllocationScores(data []byte) (map[allocationScoreKey]float64, error) {
+	scores := make(map[allocationScoreKey]float64)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		match := allocationScoreLine.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+
+		score, err := parseScore(match[3])
+		if err != nil {
+			log.Warnf("resource %s on %s: invalid allocation score '%s'", match[1], match[2], match[3])
+			continue
+		}
+
+		scores[allocationScoreKey{match[1], match[2]}] = score
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return scores, err
+	}
+
+	return scores, nil
+}
+
+// allocationScoresAllowed returns the resources of a comma separated list,
+// ignoring spaces and empty entries.
+func allocationScoresAllowed(value string) []string {
+	var allowed []string
+
+	for _, resource := range strings.Split(value, ",") {
+		if resource = strings.TrimSpace(resource); resource != "" {
+			allowed = append(allowed, resource)
+		}
+	}
+
+	return allowed
+}
+
+// allocationScoreAllowed returns whether the score of a resource is
+// exported. Clone instances, e.g. drbd-r0:1, match their primitive.
+func allocationScoreAllowed(resource string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	return stringInSlice(strings.SplitN(resource, ":", 2)[0], allowed)
+}
+
+// boundScore maps an infinite score to the given bound.
+func boundScore(score, bound float64) float64 {
+	if math.IsInf(score, 1) {
+		return bound
+	}
+
+	if math.IsInf(score, -1) {
+		return -bound
+	}
+
+	return score
+}
+
+// getAllocationScoresInfo returns crm_simulate allocation scores information
+func (c *allocationScoresCollector) getAllocationScoresInfo(ch chan<- prometheus.Metric) error {
+	outBytes, err := c.getCrmSimulate()
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	scores, err := parseAllocationScores(outBytes)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeAllocationScores(ch, scores, allocationScoresAllowed(*allocationScoresResources),
+		*allocationScoresInfinity)
+
+	return nil
+}
+
+// expose allocation scores metrics
+func (c *allocationScoresCollector) exposeAllocationScores(ch chan<- prometheus.Metric,
+	scores map[allocationScoreKey]float64, allowed []string, infinity float64) {
+	for key, score := range scores {
+		if !allocationScoreAllowed(key.Resource, allowed) {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.allocationScore,
+			prometheus.GaugeValue, boundScore(score, infinity), key.Resource, key.Node)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"math"
+	"testing"
+)
+
+func TestParseAllocationScores(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCrmSimulate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseAllocationScores(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dataStr) != 12 {
+		t.Fatalf("scores: %v!=12", len(dataStr))
+	}
+
+	if dataStr[allocationScoreKey{"lustre-mdt", "lustre-mds1"}] != 100 {
+		t.Fatalf("lustre-mdt on lustre-mds1: %v!=100",
+			dataStr[allocationScoreKey{"lustre-mdt", "lustre-mds1"}])
+	}
+
+	if !math.IsInf(dataStr[allocationScoreKey{"lustre-mgs", "lustre-mds1"}], -1) {
+		t.Fatalf("lustre-mgs on lustre-mds1: %v!=-Inf",
+			dataStr[allocationScoreKey{"lustre-mgs", "lustre-mds1"}])
+	}
+}
+
+func TestAllocationScoreAllowed(t *testing.T) {
+	allowed := allocationScoresAllowed("drbd-r0, failover,,")
+	if len(allowed) != 2 || allowed[1] != "failover" {
+		t.Fatalf("allowed: %q!=[drbd-r0 failover]", allowed)
+	}
+
+	if !allocationScoreAllowed("drbd-r0:1", allowed) {
+		t.Fatalf("drbd-r0:1 not allowed")
+	}
+
+	if allocationScoreAllowed("lustre-mdt", allowed) {
+		t.Fatalf("lustre-mdt allowed")
+	}
+
+	if !allocationScoreAllowed("lustre-mdt", allocationScoresAllowed("")) {
+		t.Fatalf("lustre-mdt not allowed without allowlist")
+	}
+}
+
+func TestBoundScore(t *testing.T) {
+	if boundScore(math.Inf(1), 1000000) != 1000000 {
+		t.Fatalf("boundScore(+Inf): %v!=1000000", boundScore(math.Inf(1), 1000000))
+	}
+
+	if boundScore(math.Inf(-1), 1000000) != -1000000 {
+		t.Fatalf("boundScore(-Inf): %v!=-1000000", boundScore(math.Inf(-1), 1000000))
+	}
+
+	if boundScore(100, 1000000) != 100 {
+		t.Fatalf("boundScore(100): %v!=100", boundScore(100, 1000000))
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	boothPeerTimeout = kingpin.Flag("collector.booth.peer-timeout",
+		"Time since the last message after which a booth peer is considered unreachable.").Default("60s").Duration()
+)
+
+type boothCollector struct {
+	boothTicketInfo        *prometheus.Desc
+	boothTicketExpiry      *prometheus.Desc
+	boothTicketCommitIndex *prometheus.Desc
+	boothPeerLastSeen      *prometheus.Desc
+	boothPeerReachable     *prometheus.Desc
+	boothPeerPackets       *prometheus.Desc
+	boothPeerErrors        *prometheus.Desc
+}
+
+func init() {
+	registerCollector("booth", defaultDisabled, NewBoothCollector)
+}
+
+// NewBoothCollector returns a new Collector exposing booth tickets and peers.
+func NewBoothCollector() (Collector, error) {
+	return &boothCollector{
+		boothTicketInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "booth_ticket", "info"),
+			"Booth ticket leader and owner site, empty when the ticket has none.",
+			[]string{"ticket", "leader", "owner"}, nil,
+		),
+		boothTicketExpiry: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "booth_ticket", "expiry_timestamp_seconds"),
+			"Time at which the booth ticket grant expires, in unixtime.",
+			[]string{"ticket"}, nil,
+		),
+		boothTicketCommitIndex: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "booth_ticket", "commit_index"),
+			"Commit index of the booth ticket.",
+			[]string{"ticket"}, nil,
+		),
+		boothPeerLastSeen: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "booth_peer", "last_seen_timestamp_seconds"),
+			"Time of the last message received from the booth peer, in unixtime.",
+			[]string{"peer", "type"}, nil,
+		),
+		boothPeerReachable: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "booth_peer", "reachable"),
+			fmt.Sprintf("Whether a message was received from the booth peer within the peer timeout (%s).",
+				boothPeerTimeout.String()),
+			[]string{"peer", "type"}, nil,
+		),
+		boothPeerPackets: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "booth_peer", "packets_total"),
+			"Number of packets exchanged with the booth peer, by direction.",
+			[]string{"peer", "type", "direction"}, nil,
+		),
+		boothPeerErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "booth_peer", "errors_total"),
+			"Number of packet errors with the booth peer, by direction.",
+			[]string{"peer", "type", "direction"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*boothCollector).getBoothInfo to get the booth tickets and
+// peers metrics.
+func (c *boothCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getBoothInfo(ch, time.Now())
+	if err != nil {
+		return fmt.Errorf("couldn't get booth information: %s", err)
+	}
+
+	return nil
+}
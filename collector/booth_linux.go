@@ -0,0 +1,276 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// boothTimeLayout is the layout of the times printed by booth, in local time.
+const boothTimeLayout = "2006-01-02 15:04:05"
+
+// BoothTicketStruct struct stores a ticket of the booth list output.
+type BoothTicketStruct struct {
+	Name   string
+	Leader string
+	Owner  string
+	Expiry time.Time
+	Commit float64
+}
+
+// BoothPeerStruct struct stores a site or arbitrator of the booth peers output.
+type BoothPeerStruct struct {
+	Address     string
+	Type        string
+	LastSeen    time.Time
+	SentPackets float64
+	SentErrors  float64
+	RecvPackets float64
+	RecvErrors  float64
+	HasCounters bool
+}
+
+// execute booth utility.
+func boothExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*boothPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *boothPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// parseBoothTime returns the time of a booth date, zero for "never" or an
+// unparsable value.
+func parseBoothTime(value string) time.Time {
+	t, err := time.ParseInLocation(boothTimeLayout, strings.TrimSpace(value), time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// parseBoothList returns the tickets of the booth list output, e.g.
+// "ticket: ticketA, leader: 10.0.0.1, expires: 2018-06-29 16:40:44, commit: 33".
+// Booth 0.x reports the site holding the ticket as "owner" and its ballot
+// instead of the commit index.
+func parseBoothList(data []byte) []BoothTicketStruct {
+	var tickets []BoothTicketStruct
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "ticket:") {
+			continue
+		}
+
+		var ticket BoothTicketStruct
+
+		for _, field := range strings.Split(line, ",") {
+			kv := strings.SplitN(field, ":", 2)
+			if len(kv) != 2 {
+				continue
+			}
+
+			key := strings.TrimSpace(kv[0])
+			value := strings.TrimSpace(kv[1])
+			if value == "NONE" {
+				value = ""
+			}
+
+			switch key {
+			case "ticket":
+				ticket.Name = value
+			case "leader":
+				ticket.Leader = value
+			case "owner":
+				ticket.Owner = value
+			case "expires":
+				ticket.Expiry = parseBoothTime(value)
+			case "commit", "ballot":
+				ticket.Commit = parseFloat(value)
+			}
+		}
+
+		if ticket.Name == "" {
+			continue
+		}
+
+		// Since booth 1.0 the leader is the site granted the ticket.
+		if ticket.Owner == "" {
+			ticket.Owner = ticket.Leader
+		}
+
+		tickets = append(tickets, ticket)
+	}
+
+	return tickets
+}
+
+// parseBoothPacketCounters returns the "pkts" and "error" counters of a
+// booth peers "Sent" or "Recv" line, e.g. "Sent pkts:120 error:0 resends:0".
+func parseBoothPacketCounters(fields []string) (float64, float64) {
+	var packets, errors float64
+
+	for _, field := range fields {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(kv[1], 64)
+		if err != nil {
+			continue
+		}
+
+		switch kv[0] {
+		case "pkts":
+			packets = value
+		case "error":
+			errors = value
+		}
+	}
+
+	return packets, errors
+}
+
+// parseBoothPeers returns the sites and arbitrators of the booth peers
+// output, e.g. "site  10.0.0.2, last recv: 2018-06-29 16:40:40" followed by
+// its "Sent" and "Recv" packet counters.
+func parseBoothPeers(data []byte) []BoothPeerStruct {
+	var peers []BoothPeerStruct
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "site", "arbitrator":
+			peer := BoothPeerStruct{
+				Address: strings.TrimSuffix(fields[1], ","),
+				Type:    fields[0],
+			}
+
+			line := scanner.Text()
+			if i := strings.Index(line, "last recv:"); i >= 0 {
+				peer.LastSeen = parseBoothTime(line[i+len("last recv:"):])
+			}
+
+			peers = append(peers, peer)
+		case "Sent", "Recv":
+			if len(peers) == 0 {
+				continue
+			}
+
+			peer := &peers[len(peers)-1]
+			packets, errors := parseBoothPacketCounters(fields[1:])
+			if fields[0] == "Sent" {
+				peer.SentPackets, peer.SentErrors = packets, errors
+			} else {
+				peer.RecvPackets, peer.RecvErrors = packets, errors
+			}
+			peer.HasCounters = true
+		}
+	}
+
+	return peers
+}
+
+// getBoothInfo returns booth information
+func (c *boothCollector) getBoothInfo(ch chan<- prometheus.Metric, now time.Time) error {
+	listRaw, err := boothExec("list")
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeBoothTickets(ch, parseBoothList(listRaw))
+
+	peersRaw, err := boothExec("peers")
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeBoothPeers(ch, parseBoothPeers(peersRaw), now)
+
+	return nil
+}
+
+// expose booth tickets metrics
+func (c *boothCollector) exposeBoothTickets(ch chan<- prometheus.Metric, tickets []BoothTicketStruct) {
+	for _, ticket := range tickets {
+		ch <- prometheus.MustNewConstMetric(c.boothTicketInfo,
+			prometheus.GaugeValue, 1.0, ticket.Name, ticket.Leader, ticket.Owner)
+
+		ch <- prometheus.MustNewConstMetric(c.boothTicketCommitIndex,
+			prometheus.GaugeValue, ticket.Commit, ticket.Name)
+
+		if !ticket.Expiry.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.boothTicketExpiry,
+				prometheus.GaugeValue, float64(ticket.Expiry.Unix()), ticket.Name)
+		}
+	}
+}
+
+// expose booth peers metrics
+func (c *boothCollector) exposeBoothPeers(ch chan<- prometheus.Metric, peers []BoothPeerStruct, now time.Time) {
+	for _, peer := range peers {
+		if !peer.LastSeen.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.boothPeerLastSeen,
+				prometheus.GaugeValue, float64(peer.LastSeen.Unix()), peer.Address, peer.Type)
+		}
+
+		if !peer.LastSeen.IsZero() && now.Sub(peer.LastSeen) <= *boothPeerTimeout {
+			ch <- prometheus.MustNewConstMetric(c.boothPeerReachable,
+				prometheus.GaugeValue, 1.0, peer.Address, peer.Type)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.boothPeerReachable,
+				prometheus.GaugeValue, 0.0, peer.Address, peer.Type)
+		}
+
+		if !peer.HasCounters {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.boothPeerPackets,
+			prometheus.CounterValue, peer.SentPackets, peer.Address, peer.Type, "sent")
+		ch <- prometheus.MustNewConstMetric(c.boothPeerPackets,
+			prometheus.CounterValue, peer.RecvPackets, peer.Address, peer.Type, "received")
+		ch <- prometheus.MustNewConstMetric(c.boothPeerErrors,
+			prometheus.CounterValue, peer.SentErrors, peer.Address, peer.Type, "sent")
+		ch <- prometheus.MustNewConstMetric(c.boothPeerErrors,
+			prometheus.CounterValue, peer.RecvErrors, peer.Address, peer.Type, "received")
+	}
+}
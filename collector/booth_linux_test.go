@@ -0,0 +1,91 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+const (
+	testBoothList  = "fixtures/booth_list.txt"
+	testBoothPeers = "fixtures/booth_peers.txt"
+)
+
+func TestParseBoothList(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testBoothList)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tickets := parseBoothList(dataByte)
+	if len(tickets) != 2 {
+		t.Fatalf("tickets: %v!=2", len(tickets))
+	}
+
+	ticket := tickets[0]
+	if ticket.Name != "ticketA" || ticket.Leader != "10.0.0.1" || ticket.Owner != "10.0.0.1" {
+		t.Fatalf("ticket: %+v!=ticketA owned by 10.0.0.1", ticket)
+	}
+
+	expiry := time.Date(2018, 6, 29, 16, 40, 44, 0, time.Local)
+	if !ticket.Expiry.Equal(expiry) {
+		t.Fatalf("ticket '%s' expiry: %v!=%v", ticket.Name, ticket.Expiry, expiry)
+	}
+
+	if ticket.Commit != 33 {
+		t.Fatalf("ticket '%s' commit: %v!=33", ticket.Name, ticket.Commit)
+	}
+
+	ticket = tickets[1]
+	if ticket.Leader != "" || !ticket.Expiry.IsZero() {
+		t.Fatalf("ticket '%s': %+v has a leader", ticket.Name, ticket)
+	}
+}
+
+func TestParseBoothPeers(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testBoothPeers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	peers := parseBoothPeers(dataByte)
+	if len(peers) != 2 {
+		t.Fatalf("peers: %v!=2", len(peers))
+	}
+
+	site := peers[0]
+	if site.Address != "10.0.0.2" || site.Type != "site" {
+		t.Fatalf("peer: %v %v!=site 10.0.0.2", site.Type, site.Address)
+	}
+
+	lastSeen := time.Date(2018, 6, 29, 16, 40, 40, 0, time.Local)
+	if !site.LastSeen.Equal(lastSeen) {
+		t.Fatalf("peer '%s' last seen: %v!=%v", site.Address, site.LastSeen, lastSeen)
+	}
+
+	if site.SentPackets != 120 || site.RecvPackets != 118 || site.RecvErrors != 1 {
+		t.Fatalf("peer '%s' counters: %+v", site.Address, site)
+	}
+
+	arbitrator := peers[1]
+	if arbitrator.Type != "arbitrator" || !arbitrator.LastSeen.IsZero() {
+		t.Fatalf("peer '%s': %+v was seen", arbitrator.Address, arbitrator)
+	}
+
+	if arbitrator.SentErrors != 3 {
+		t.Fatalf("peer '%s' sent errors: %v!=3", arbitrator.Address, arbitrator.SentErrors)
+	}
+}
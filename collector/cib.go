@@ -0,0 +1,108 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type cibCollector struct {
+	cibQuerier
+
+	cibAdminEpoch             *prometheus.Desc
+	cibEpoch                  *prometheus.Desc
+	cibNumUpdates             *prometheus.Desc
+	cibClusterProperty        *prometheus.Desc
+	cibStonithTimeout         *prometheus.Desc
+	cibClusterRecheckInterval *prometheus.Desc
+	cibResourceMetaAttribute  *prometheus.Desc
+	cibResourceManaged        *prometheus.Desc
+	cibResourceStickiness     *prometheus.Desc
+	cibResourceFailureTimeout *prometheus.Desc
+}
+
+func init() {
+	registerCollector("cib", defaultEnabled, NewCibCollector)
+}
+
+// NewCibCollector returns a new Collector exposing the CIB configuration.
+func NewCibCollector() (Collector, error) {
+	return &cibCollector{
+		cibAdminEpoch: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cib", "admin_epoch"),
+			"CIB admin_epoch, incremented by the administrator.",
+			nil, nil,
+		),
+		cibEpoch: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cib", "epoch"),
+			"CIB epoch, incremented on every configuration change.",
+			nil, nil,
+		),
+		cibNumUpdates: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cib", "num_updates"),
+			"CIB num_updates, incremented on every status change.",
+			nil, nil,
+		),
+		cibClusterProperty: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cib", "cluster_property"),
+			"A metric with a constant '1' value labeled by crm_config cluster property name and value, except the ones updated by Pacemaker itself.",
+			[]string{"name", "value"}, nil,
+		),
+		cibStonithTimeout: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cib", "stonith_timeout_seconds"),
+			"Configured stonith-timeout cluster property in seconds.",
+			nil, nil,
+		),
+		cibClusterRecheckInterval: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cib", "cluster_recheck_interval_seconds"),
+			"Configured cluster-recheck-interval cluster property in seconds.",
+			nil, nil,
+		),
+		cibResourceMetaAttribute: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cib", "resource_meta_attribute"),
+			"A metric with a constant '1' value labeled by resource, resource kind, meta attribute name and value.",
+			[]string{"resource", "kind", "name", "value"}, nil,
+		),
+		cibResourceManaged: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cib", "resource_managed"),
+			"Whether the is-managed meta attribute of the resource is true.",
+			[]string{"resource", "kind"}, nil,
+		),
+		cibResourceStickiness: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cib", "resource_stickiness"),
+			"Configured resource-stickiness meta attribute of the resource.",
+			[]string{"resource", "kind"}, nil,
+		),
+		cibResourceFailureTimeout: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cib", "resource_failure_timeout_seconds"),
+			"Configured failure-timeout meta attribute of the resource in seconds.",
+			[]string{"resource", "kind"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*cibCollector).getCibInfo to get the CIB configuration
+// metrics.
+func (c *cibCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getCibInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get cib information: %s", err)
+	}
+
+	return nil
+}
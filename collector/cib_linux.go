@@ -0,0 +1,379 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+var (
+	// pacemakerIntervalUnits maps the interval units to seconds.
+	pacemakerIntervalUnits = map[string]float64{
+		"":     1,
+		"s":    1,
+		"sec":  1,
+		"ms":   1e-3,
+		"msec": 1e-3,
+		"us":   1e-6,
+		"usec": 1e-6,
+		"m":    60,
+		"min":  60,
+		"h":    3600,
+		"hr":   3600,
+	}
+	// pacemakerInterval matches a number followed by an optional unit.
+	pacemakerInterval = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([a-z]*)$`)
+	// iso8601Duration matches an ISO 8601 duration without years or months.
+	iso8601Duration = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+	// cibVolatileProperties are the cluster properties updated by Pacemaker
+	// itself, whose values would create a new time series on every change.
+	cibVolatileProperties = []string{"last-lrm-refresh"}
+)
+
+// cibResourceStruct stores the meta attributes of a configured resource.
+type cibResourceStruct struct {
+	ID             string
+	Kind           string
+	MetaAttributes []NVSetStruct
+}
+
+// cibPrimitiveCloneStruct stores the Pacemaker primitive and clone managing
+// a resource outside of Pacemaker, e.g. a DRBD resource or a volume group.
+type cibPrimitiveCloneStruct struct {
+	ID      string
+	CloneID string
+}
+
+// execute cibadmin utility.
+func cibadminExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*cibadminPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *cibadminPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// parseCibXML returns an XML structs.
+func parseCibXML(data []byte) (CibStruct, error) {
+	var cibOut CibStruct
+
+	err := xml.Unmarshal(data, &cibOut)
+	if err != nil {
+		log.Errorln(err)
+		return cibOut, err
+	}
+
+	return cibOut, nil
+}
+
+// cibQueryStruct struct stores the CIB queried once and shared by all the
+// collectors of a scrape.
+type cibQueryStruct struct {
+	once      sync.Once
+	cibStruct CibStruct
+	err       error
+}
+
+// newCibQuery returns a CIB query to be shared during a scrape.
+func newCibQuery() *cibQueryStruct {
+	return &cibQueryStruct{}
+}
+
+// get returns the CIB, calling cibadmin on first use only.
+func (q *cibQueryStruct) get() (CibStruct, error) {
+	q.once.Do(func() {
+		var outBytes []byte
+
+		outBytes, q.err = cibadminExec("--query", "--local")
+		if q.err != nil {
+			return
+		}
+
+		q.cibStruct, q.err = parseCibXML(outBytes)
+	})
+
+	return q.cibStruct, q.err
+}
+
+// cibQuerier is embedded by the collectors reading the CIB.
+type cibQuerier struct {
+	cibQuery *cibQueryStruct
+}
+
+// setCibQuery implements the cibQueryCollector interface.
+func (c *cibQuerier) setCibQuery(q *cibQueryStruct) {
+	c.cibQuery = q
+}
+
+// getCib returns the CIB shared by the scrape, querying it on its own when
+// the collector is used outside of a PacemakerCollector.
+func (c *cibQuerier) getCib() (CibStruct, error) {
+	if c.cibQuery == nil {
+		c.cibQuery = newCibQuery()
+	}
+
+	return c.cibQuery.get()
+}
+
+// nvSetValue returns the first value found for any of the given names.
+func nvSetValue(nvSets []NVSetStruct, names ...string) (string, bool) {
+	for _, nvSet := range nvSets {
+		for _, nvPair := range nvSet.NVPair {
+			if stringInSlice(nvPair.Name, names) {
+				return nvPair.Value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// nvSetValues returns the name/value pairs of the sets, the first value
+// wins when a name is set more than once.
+func nvSetValues(nvSets []NVSetStruct) ([]string, map[string]string) {
+	var names []string
+	values := make(map[string]string)
+
+	for _, nvSet := range nvSets {
+		for _, nvPair := range nvSet.NVPair {
+			if _, ok := values[nvPair.Name]; ok {
+				continue
+			}
+
+			names = append(names, nvPair.Name)
+			values[nvPair.Name] = nvPair.Value
+		}
+	}
+
+	return names, values
+}
+
+// parsePacemakerInterval returns a Pacemaker interval specification, like
+// "120s", "5min" or "PT5M", in seconds.
+func parsePacemakerInterval(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+
+	if match := pacemakerInterval.FindStringSubmatch(value); match != nil {
+		scale, ok := pacemakerIntervalUnits[match[2]]
+		if !ok {
+			return 0, fmt.Errorf("unknown interval unit '%s'", match[2])
+		}
+
+		number, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, err
+		}
+
+		return number * scale, nil
+	}
+
+	if match := iso8601Duration.FindStringSubmatch(value); match != nil && value != "P" {
+		var seconds float64
+
+		for i, scale := range []float64{604800, 86400, 3600, 60, 1} {
+			if match[i+1] == "" {
+				continue
+			}
+
+			number, err := strconv.ParseFloat(match[i+1], 64)
+			if err != nil {
+				return 0, err
+			}
+
+			seconds += number * scale
+		}
+
+		return seconds, nil
+	}
+
+	return 0, fmt.Errorf("invalid interval '%s'", value)
+}
+
+// parsePacemakerBool returns a Pacemaker boolean, accepting the same
+// spellings as crm_is_true.
+func parsePacemakerBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "on", "yes", "y", "1":
+		return true, nil
+	case "false", "off", "no", "n", "0":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid boolean '%s'", value)
+}
+
+// parseScore returns a Pacemaker score, mapping INFINITY to +Inf and
+// -INFINITY to -Inf.
+func parseScore(value string) (float64, error) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "INFINITY", "+INFINITY":
+		return math.Inf(1), nil
+	case "-INFINITY":
+		return math.Inf(-1), nil
+	}
+
+	return strconv.ParseFloat(strings.TrimSpace(value), 64)
+}
+
+// cibResources returns all configured resources, including the members of
+// groups and clones.
+func cibResources(resources CibResourcesStruct) []cibResourceStruct {
+	var result []cibResourceStruct
+
+	addPrimitives := func(primitives []CibPrimitiveStruct) {
+		for _, primitive := range primitives {
+			result = append(result, cibResourceStruct{primitive.ID, "primitive", primitive.MetaAttributes})
+		}
+	}
+
+	addGroups := func(groups []CibGroupStruct) {
+		for _, group := range groups {
+			result = append(result, cibResourceStruct{group.ID, "group", group.MetaAttributes})
+			addPrimitives(group.Primitive)
+		}
+	}
+
+	addClones := func(clones []CibCloneStruct, kind string) {
+		for _, clone := range clones {
+			result = append(result, cibResourceStruct{clone.ID, kind, clone.MetaAttributes})
+			addPrimitives(clone.Primitive)
+			addGroups(clone.Group)
+		}
+	}
+
+	addPrimitives(resources.Primitive)
+	addGroups(resources.Group)
+	addClones(resources.Clone, "clone")
+	addClones(resources.Master, "master")
+
+	return result
+}
+
+// getCibInfo returns cibadmin information
+func (c *cibCollector) getCibInfo(ch chan<- prometheus.Metric) error {
+	cibStruct, err := c.getCib()
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeCibVersion(ch, cibStruct)
+	c.exposeCibClusterProperties(ch, cibStruct)
+	c.exposeCibResources(ch, cibStruct)
+
+	return nil
+}
+
+// expose CIB version metrics
+func (c *cibCollector) exposeCibVersion(ch chan<- prometheus.Metric, cibStruct CibStruct) {
+	ch <- prometheus.MustNewConstMetric(c.cibAdminEpoch,
+		prometheus.GaugeValue, cibStruct.AdminEpoch)
+	ch <- prometheus.MustNewConstMetric(c.cibEpoch,
+		prometheus.GaugeValue, cibStruct.Epoch)
+	ch <- prometheus.MustNewConstMetric(c.cibNumUpdates,
+		prometheus.GaugeValue, cibStruct.NumUpdates)
+}
+
+// expose CIB cluster properties metrics
+func (c *cibCollector) exposeCibClusterProperties(ch chan<- prometheus.Metric, cibStruct CibStruct) {
+	names, values := nvSetValues(cibStruct.Configuration.CrmConfig.ClusterPropertySet)
+
+	for _, name := range names {
+		if stringInSlice(name, cibVolatileProperties) {
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.cibClusterProperty,
+			prometheus.GaugeValue, 1.0, name, values[name])
+	}
+
+	intervals := map[string]*prometheus.Desc{
+		"stonith-timeout":          c.cibStonithTimeout,
+		"cluster-recheck-interval": c.cibClusterRecheckInterval,
+	}
+
+	for name, desc := range intervals {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+
+		seconds, err := parsePacemakerInterval(value)
+		if err != nil {
+			log.Warnf("cluster property %s: %v", name, err)
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, seconds)
+	}
+}
+
+// expose CIB resources meta attributes metrics
+func (c *cibCollector) exposeCibResources(ch chan<- prometheus.Metric, cibStruct CibStruct) {
+	for _, resource := range cibResources(cibStruct.Configuration.Resources) {
+		names, values := nvSetValues(resource.MetaAttributes)
+
+		for _, name := range names {
+			ch <- prometheus.MustNewConstMetric(c.cibResourceMetaAttribute,
+				prometheus.GaugeValue, 1.0, resource.ID, resource.Kind,
+				name, values[name])
+		}
+
+		// Resources are managed unless is-managed is set to false.
+		if managed, err := parsePacemakerBool(values["is-managed"]); err == nil && !managed {
+			ch <- prometheus.MustNewConstMetric(c.cibResourceManaged,
+				prometheus.GaugeValue, 0.0, resource.ID, resource.Kind)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.cibResourceManaged,
+				prometheus.GaugeValue, 1.0, resource.ID, resource.Kind)
+		}
+
+		if value, ok := values["resource-stickiness"]; ok {
+			score, err := parseScore(value)
+			if err != nil {
+				log.Warnf("resource %s: invalid resource-stickiness '%s'", resource.ID, value)
+			} else {
+				ch <- prometheus.MustNewConstMetric(c.cibResourceStickiness,
+					prometheus.GaugeValue, score, resource.ID, resource.Kind)
+			}
+		}
+
+		if value, ok := values["failure-timeout"]; ok {
+			seconds, err := parsePacemakerInterval(value)
+			if err != nil {
+				log.Warnf("resource %s: %v", resource.ID, err)
+			} else {
+				ch <- prometheus.MustNewConstMetric(c.cibResourceFailureTimeout,
+					prometheus.GaugeValue, seconds, resource.ID, resource.Kind)
+			}
+		}
+	}
+}
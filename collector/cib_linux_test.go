@@ -0,0 +1,104 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"math"
+	"testing"
+)
+
+func TestParseCibXML(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCib)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCibXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dataStr.Epoch != 245 || dataStr.NumUpdates != 12 || dataStr.AdminEpoch != 0 {
+		t.Fatalf("admin_epoch/epoch/num_updates: %v/%v/%v!=0/245/12",
+			dataStr.AdminEpoch, dataStr.Epoch, dataStr.NumUpdates)
+	}
+
+	_, properties := nvSetValues(dataStr.Configuration.CrmConfig.ClusterPropertySet)
+	if properties["no-quorum-policy"] != "ignore" {
+		t.Fatalf("no-quorum-policy: %v!=ignore", properties["no-quorum-policy"])
+	}
+
+	resources := cibResources(dataStr.Configuration.Resources)
+	if len(resources) != 18 {
+		t.Fatalf("resources: %v!=18", len(resources))
+	}
+
+	for _, resource := range resources {
+		if resource.ID == "failover" && resource.Kind != "group" {
+			t.Fatalf("failover kind: %v!=group", resource.Kind)
+		}
+	}
+}
+
+func TestParsePacemakerInterval(t *testing.T) {
+	intervals := map[string]float64{
+		"120":       120,
+		"120s":      120,
+		"5min":      300,
+		"1h":        3600,
+		"500ms":     0.5,
+		"PT5M":      300,
+		"P1DT1H":    90000,
+		"PT1H30M5S": 5405,
+	}
+
+	for value, expected := range intervals {
+		seconds, err := parsePacemakerInterval(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if seconds != expected {
+			t.Fatalf("parsePacemakerInterval(%s): %v!=%v", value, seconds, expected)
+		}
+	}
+
+	for _, value := range []string{"", "P", "5 fortnights", "abc"} {
+		if _, err := parsePacemakerInterval(value); err == nil {
+			t.Fatalf("parsePacemakerInterval(%s): err==nil", value)
+		}
+	}
+}
+
+func TestParseScore(t *testing.T) {
+	scores := map[string]float64{
+		"100":       100,
+		"-50":       -50,
+		"INFINITY":  math.Inf(1),
+		"+INFINITY": math.Inf(1),
+		"-INFINITY": math.Inf(-1),
+	}
+
+	for value, expected := range scores {
+		score, err := parseScore(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if score != expected {
+			t.Fatalf("parseScore(%s): %v!=%v", value, score, expected)
+		}
+	}
+}
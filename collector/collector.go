@@ -44,8 +44,8 @@ var (
 )
 
 const (
-	defaultEnabled = true
-	// defaultDisabled = false
+	defaultEnabled  = true
+	defaultDisabled = false
 )
 
 var (
@@ -94,6 +94,8 @@ func NewPacemakerCollector(filters ...string) (*PacemakerCollector, error) {
 	}
 
 	collectors := make(map[string]Collector)
+	cibQuery := newCibQuery()
+	crmSimulateQuery := newCrmSimulateQuery()
 
 	for key, enabled := range collectorState {
 		if *enabled {
@@ -102,6 +104,14 @@ func NewPacemakerCollector(filters ...string) (*PacemakerCollector, error) {
 				return nil, err
 			}
 
+			if c, ok := collector.(cibQueryCollector); ok {
+				c.setCibQuery(cibQuery)
+			}
+
+			if c, ok := collector.(crmSimulateQueryCollector); ok {
+				c.setCrmSimulateQuery(crmSimulateQuery)
+			}
+
 			if len(f) == 0 || f[key] {
 				collectors[key] = collector
 			}
@@ -157,6 +167,19 @@ type Collector interface {
 	Update(ch chan<- prometheus.Metric) error
 }
 
+// cibQueryCollector is implemented by the collectors reading the CIB, so
+// that a single cibadmin call is shared by all of them during a scrape.
+type cibQueryCollector interface {
+	setCibQuery(q *cibQueryStruct)
+}
+
+// crmSimulateQueryCollector is implemented by the collectors reading
+// crm_simulate, so that a single crm_simulate call is shared by all of them
+// during a scrape.
+type crmSimulateQueryCollector interface {
+	setCrmSimulateQuery(q *crmSimulateQueryStruct)
+}
+
 type typedDesc struct {
 	desc      *prometheus.Desc
 	valueType prometheus.ValueType
@@ -0,0 +1,78 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type constraintsCollector struct {
+	cibQuerier
+
+	constraints          *prometheus.Desc
+	constraintMoveScore  *prometheus.Desc
+	constraintMoveExpiry *prometheus.Desc
+	constraintExpiry     *prometheus.Desc
+	constraintExpired    *prometheus.Desc
+}
+
+func init() {
+	registerCollector("constraints", defaultEnabled, NewConstraintsCollector)
+}
+
+// NewConstraintsCollector returns a new Collector exposing the CIB constraints.
+func NewConstraintsCollector() (Collector, error) {
+	return &constraintsCollector{
+		constraints: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "constraints"),
+			"Number of constraints by type.",
+			[]string{"type"}, nil,
+		),
+		constraintMoveScore: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "constraint", "move_score"),
+			"Score of a cli-ban or cli-prefer location constraint left over from crm_resource --move or --ban.",
+			[]string{"id", "resource", "node", "type"}, nil,
+		),
+		constraintMoveExpiry: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "constraint", "move_expiry_timestamp_seconds"),
+			"Expiry of a cli-ban or cli-prefer location constraint created with a lifetime, in seconds since epoch.",
+			[]string{"id", "resource", "node", "type"}, nil,
+		),
+		constraintExpiry: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "constraint", "expiry_timestamp_seconds"),
+			"Time after which the date expressions of the location constraint rules no longer match, in seconds since epoch.",
+			[]string{"id", "resource"}, nil,
+		),
+		constraintExpired: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "constraint", "expired"),
+			"Whether the date expressions of the location constraint rules no longer match.",
+			[]string{"id", "resource"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*constraintsCollector).getConstraintsInfo to get the CIB
+// constraints metrics.
+func (c *constraintsCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getConstraintsInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get constraints information: %s", err)
+	}
+
+	return nil
+}
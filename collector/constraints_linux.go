@@ -0,0 +1,303 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// cibDateLayouts are the date formats used in CIB date expressions.
+var cibDateLayouts = []string{
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// moveConstraintStruct stores a location constraint created by
+// crm_resource --move or --ban.
+type moveConstraintStruct struct {
+	ID       string
+	Resource string
+	Node     string
+	Type     string
+	Score    float64
+	Expiry   time.Time
+}
+
+// parseCibDate returns the time of a CIB date, which is in UTC unless it
+// has an offset.
+func parseCibDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range cibDateLayouts {
+		date, err := time.Parse(layout, value)
+		if err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date '%s'", value)
+}
+
+// dateExpressionEnd returns the time after which a date expression no
+// longer matches. Expressions without an end, like date_spec or gt, never
+// expire.
+func dateExpressionEnd(dateExpression CibDateExpressionStruct) (time.Time, bool) {
+	switch dateExpression.Operation {
+	case "lt", "in_range":
+	default:
+		return time.Time{}, false
+	}
+
+	if dateExpression.End != "" {
+		end, err := parseCibDate(dateExpression.End)
+		if err != nil {
+			log.Warnf("date_expression %s: %v", dateExpression.ID, err)
+			return time.Time{}, false
+		}
+
+		return end, true
+	}
+
+	if dateExpression.Operation != "in_range" || dateExpression.Duration == nil {
+		return time.Time{}, false
+	}
+
+	start, err := parseCibDate(dateExpression.Start)
+	if err != nil {
+		log.Warnf("date_expression %s: %v", dateExpression.ID, err)
+		return time.Time{}, false
+	}
+
+	duration := dateExpression.Duration
+
+	return start.AddDate(duration.Years, duration.Months, 7*duration.Weeks+duration.Days).
+		Add(time.Duration(duration.Hours)*time.Hour +
+			time.Duration(duration.Minutes)*time.Minute +
+			time.Duration(duration.Seconds)*time.Second), true
+}
+
+// ruleExpiry returns the time after which a rule no longer matches. An "and"
+// rule expires with its first expiring date expression, an "or" rule only
+// when all of its expressions expire.
+func ruleExpiry(rule CibRuleStruct) (time.Time, bool) {
+	var ends []time.Time
+
+	for _, dateExpression := range rule.DateExpression {
+		if end, ok := dateExpressionEnd(dateExpression); ok {
+			ends = append(ends, end)
+		}
+	}
+
+	for _, subRule := range rule.Rule {
+		if end, ok := ruleExpiry(subRule); ok {
+			ends = append(ends, end)
+		}
+	}
+
+	if len(ends) == 0 {
+		return time.Time{}, false
+	}
+
+	if rule.BooleanOp == "or" {
+		if len(ends) != len(rule.Expression)+len(rule.DateExpression)+len(rule.Rule) {
+			return time.Time{}, false
+		}
+
+		return latestTime(ends), true
+	}
+
+	earliest := ends[0]
+	for _, end := range ends[1:] {
+		if end.Before(earliest) {
+			earliest = end
+		}
+	}
+
+	return earliest, true
+}
+
+// latestTime returns the latest of the given times.
+func latestTime(times []time.Time) time.Time {
+	latest := times[0]
+	for _, t := range times[1:] {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+
+	return latest
+}
+
+// constraintExpiry returns the time after which none of the rules of a
+// location constraint match anymore.
+func constraintExpiry(location CibLocationStruct) (time.Time, bool) {
+	if len(location.Rule) == 0 {
+		return time.Time{}, false
+	}
+
+	var ends []time.Time
+
+	for _, rule := range location.Rule {
+		end, ok := ruleExpiry(rule)
+		if !ok {
+			return time.Time{}, false
+		}
+
+		ends = append(ends, end)
+	}
+
+	return latestTime(ends), true
+}
+
+// constraintCounts returns the number of constraints by type.
+func constraintCounts(constraints CibConstraintsStruct) map[string]float64 {
+	return map[string]float64{
+		"location":   float64(len(constraints.RscLocation)),
+		"colocation": float64(len(constraints.RscColocation)),
+		"order":      float64(len(constraints.RscOrder)),
+		"ticket":     float64(len(constraints.RscTicket)),
+	}
+}
+
+// parseMoveConstraints returns the cli-ban and cli-prefer location
+// constraints. With a lifetime, the node and score are in a rule ending at
+// the expiry date.
+func parseMoveConstraints(constraints CibConstraintsStruct) []moveConstraintStruct {
+	var moves []moveConstraintStruct
+
+	for _, location := range constraints.RscLocation {
+		var move moveConstraintStruct
+
+		switch {
+		case strings.HasPrefix(location.ID, "cli-ban-"):
+			move.Type = "ban"
+		case strings.HasPrefix(location.ID, "cli-prefer-"):
+			move.Type = "prefer"
+		default:
+			continue
+		}
+
+		move.ID = location.ID
+		move.Resource = location.Rsc
+		move.Node = location.Node
+		score := location.Score
+
+		for _, rule := range location.Rule {
+			if rule.Score != "" {
+				score = rule.Score
+			}
+
+			for _, expression := range rule.Expression {
+				if expression.Attribute == "#uname" && expression.Operation == "eq" {
+					move.Node = expression.Value
+				}
+			}
+
+			for _, dateExpression := range rule.DateExpression {
+				if dateExpression.Operation != "lt" {
+					continue
+				}
+
+				expiry, err := parseCibDate(dateExpression.End)
+				if err != nil {
+					log.Warnf("constraint %s: %v", location.ID, err)
+					continue
+				}
+
+				move.Expiry = expiry
+			}
+		}
+
+		value, err := parseScore(score)
+		if err != nil {
+			log.Warnf("constraint %s: invalid score '%s'", location.ID, score)
+			continue
+		}
+
+		move.Score = value
+		moves = append(moves, move)
+	}
+
+	return moves
+}
+
+// getConstraintsInfo returns the CIB constraints information
+func (c *constraintsCollector) getConstraintsInfo(ch chan<- prometheus.Metric) error {
+	cibStruct, err := c.getCib()
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeConstraints(ch, cibStruct.Configuration.Constraints)
+	c.exposeConstraintsExpiry(ch, cibStruct.Configuration.Constraints, time.Now())
+
+	return nil
+}
+
+// expose constraints metrics
+func (c *constraintsCollector) exposeConstraints(ch chan<- prometheus.Metric, constraints CibConstraintsStruct) {
+	for constraintType, count := range constraintCounts(constraints) {
+		ch <- prometheus.MustNewConstMetric(c.constraints,
+			prometheus.GaugeValue, count, constraintType)
+	}
+
+	for _, move := range parseMoveConstraints(constraints) {
+		ch <- prometheus.MustNewConstMetric(c.constraintMoveScore,
+			prometheus.GaugeValue, move.Score, move.ID, move.Resource,
+			move.Node, move.Type)
+
+		if !move.Expiry.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.constraintMoveExpiry,
+				prometheus.GaugeValue, float64(move.Expiry.Unix()), move.ID,
+				move.Resource, move.Node, move.Type)
+		}
+	}
+}
+
+// expose constraints expiry metrics
+func (c *constraintsCollector) exposeConstraintsExpiry(ch chan<- prometheus.Metric, constraints CibConstraintsStruct, now time.Time) {
+	for _, location := range constraints.RscLocation {
+		expiry, ok := constraintExpiry(location)
+		if !ok {
+			continue
+		}
+
+		resource := location.Rsc
+		if resource == "" {
+			resource = location.RscPattern
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.constraintExpiry,
+			prometheus.GaugeValue, float64(expiry.Unix()), location.ID, resource)
+
+		if expiry.Before(now) {
+			ch <- prometheus.MustNewConstMetric(c.constraintExpired,
+				prometheus.GaugeValue, 1.0, location.ID, resource)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.constraintExpired,
+				prometheus.GaugeValue, 0.0, location.ID, resource)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"math"
+	"testing"
+)
+
+func TestConstraintCounts(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCib)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCibXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := constraintCounts(dataStr.Configuration.Constraints)
+	expected := map[string]float64{"location": 6, "colocation": 1, "order": 2, "ticket": 1}
+
+	for constraintType, count := range expected {
+		if counts[constraintType] != count {
+			t.Fatalf("%s constraints: %v!=%v", constraintType, counts[constraintType], count)
+		}
+	}
+}
+
+func TestParseMoveConstraints(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCib)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCibXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moves := parseMoveConstraints(dataStr.Configuration.Constraints)
+	if len(moves) != 2 {
+		t.Fatalf("moves: %v!=2", len(moves))
+	}
+
+	ban := moves[0]
+	if ban.Type != "ban" || ban.Node != "lustre-mds1" || !math.IsInf(ban.Score, -1) ||
+		!ban.Expiry.IsZero() {
+		t.Fatalf("ban: %+v", ban)
+	}
+
+	prefer := moves[1]
+	if prefer.Type != "prefer" || prefer.Resource != "failover" ||
+		prefer.Node != "lustre-mds2" || !math.IsInf(prefer.Score, 1) {
+		t.Fatalf("prefer: %+v", prefer)
+	}
+
+	// 2018-06-29 16:40:44 +02:00
+	if prefer.Expiry.Unix() != 1530283244 {
+		t.Fatalf("prefer expiry: %v!=1530283244", prefer.Expiry.Unix())
+	}
+}
+
+func TestParseCibDate(t *testing.T) {
+	dates := map[string]int64{
+		"2018-06-29 14:40:44Z":       1530283244,
+		"2018-06-29 16:40:44 +02:00": 1530283244,
+		"2018-06-29T14:40:44Z":       1530283244,
+		"2018-06-29 14:40:44":        1530283244,
+		"2018-06-29":                 1530230400,
+	}
+
+	for value, expected := range dates {
+		date, err := parseCibDate(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if date.Unix() != expected {
+			t.Fatalf("parseCibDate(%s): %v!=%v", value, date.Unix(), expected)
+		}
+	}
+
+	if _, err := parseCibDate("tomorrow"); err == nil {
+		t.Fatalf("parseCibDate(tomorrow): err==nil")
+	}
+}
+
+func TestConstraintExpiry(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCib)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCibXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]int64{
+		"cli-prefer-failover": 1530283244,
+		// in_range starting 2018-07-01 20:00:00Z for 4 hours
+		"location-mysql-master-maintenance": 1530489600,
+	}
+
+	for _, location := range dataStr.Configuration.Constraints.RscLocation {
+		expiry, ok := constraintExpiry(location)
+
+		if expectedExpiry, found := expected[location.ID]; found {
+			if !ok || expiry.Unix() != expectedExpiry {
+				t.Fatalf("%s expiry: %v!=%v", location.ID, expiry.Unix(), expectedExpiry)
+			}
+		} else if ok {
+			t.Fatalf("%s expiry: %v, expected none", location.ID, expiry)
+		}
+	}
+}
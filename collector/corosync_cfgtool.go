@@ -0,0 +1,82 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type corosyncCfgtoolCollector struct {
+	corosyncLinkInfo      *prometheus.Desc
+	corosyncLinkFault     *prometheus.Desc
+	corosyncLinkConnected *prometheus.Desc
+	corosyncLinkEnabled   *prometheus.Desc
+	corosyncLinkMTU       *prometheus.Desc
+	corosyncNodeReachable *prometheus.Desc
+}
+
+func init() {
+	registerCollector("corosync_cfgtool", defaultEnabled, NewCorosyncCfgtoolCollector)
+}
+
+// NewCorosyncCfgtoolCollector returns a new Collector exposing corosync link information.
+func NewCorosyncCfgtoolCollector() (Collector, error) {
+	return &corosyncCfgtoolCollector{
+		corosyncLinkInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_link", "info"),
+			"A metric with a constant '1' value labeled by link ID, transport, and local address.",
+			[]string{"link_id", "transport", "address"}, nil,
+		),
+		corosyncLinkFault: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_link", "fault"),
+			"Whether the link is faulty or a peer node is not connected on it.",
+			[]string{"link_id"}, nil,
+		),
+		corosyncLinkConnected: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_link", "connected"),
+			"Whether the peer node is connected on the link.",
+			[]string{"link_id", "node_id"}, nil,
+		),
+		corosyncLinkEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_link", "enabled"),
+			"Whether the link to the peer node is enabled.",
+			[]string{"link_id", "node_id"}, nil,
+		),
+		corosyncLinkMTU: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_link", "mtu_bytes"),
+			"MTU of the link to the peer node in bytes.",
+			[]string{"link_id", "node_id"}, nil,
+		),
+		corosyncNodeReachable: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_node", "reachable"),
+			"Whether the peer node is reachable through any link.",
+			[]string{"node_id"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*corosyncCfgtoolCollector).getCorosyncCfgtoolInfo to get the
+// corosync link metrics.
+func (c *corosyncCfgtoolCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getCorosyncCfgtoolInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get corosync link information: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// CorosyncCfgStruct struct stores the corosync-cfgtool information
+type CorosyncCfgStruct struct {
+	LocalNodeID string
+	Transport   string
+	Links       []CorosyncLinkStruct
+	// Reachable is only known on the knet transport.
+	Reachable map[string]bool
+}
+
+// CorosyncLinkStruct struct stores a corosync-cfgtool link or ring
+type CorosyncLinkStruct struct {
+	LinkID  string
+	Type    string
+	Address string
+	// Status is only set for totem rings of corosync 2.
+	Status string
+	Nodes  []CorosyncLinkNodeStruct
+}
+
+// CorosyncLinkNodeStruct struct stores the status of a peer node on a link
+type CorosyncLinkNodeStruct struct {
+	NodeID    string
+	Enabled   bool
+	Connected bool
+	MTU       float64
+}
+
+// execute corosync-cfgtool utility.
+func corosyncCfgtoolExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*corosyncCfgtoolPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *corosyncCfgtoolPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// link returns the link with the given ID, adding it when missing.
+func (cfg *CorosyncCfgStruct) link(linkID string) *CorosyncLinkStruct {
+	for idx := range cfg.Links {
+		if cfg.Links[idx].LinkID == linkID {
+			return &cfg.Links[idx]
+		}
+	}
+
+	cfg.Links = append(cfg.Links, CorosyncLinkStruct{LinkID: linkID})
+
+	return &cfg.Links[len(cfg.Links)-1]
+}
+
+// node returns the peer node with the given ID, adding it when missing.
+func (link *CorosyncLinkStruct) node(nodeID string) *CorosyncLinkNodeStruct {
+	for idx := range link.Nodes {
+		if link.Nodes[idx].NodeID == nodeID {
+			return &link.Nodes[idx]
+		}
+	}
+
+	link.Nodes = append(link.Nodes, CorosyncLinkNodeStruct{NodeID: nodeID})
+
+	return &link.Nodes[len(link.Nodes)-1]
+}
+
+// fault returns whether a ring is marked faulty, or a peer node is not
+// connected or disabled on the link.
+func (link *CorosyncLinkStruct) fault() bool {
+	if strings.Contains(link.Status, "FAULTY") {
+		return true
+	}
+
+	for _, node := range link.Nodes {
+		if !node.Enabled || !node.Connected {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseCorosyncCfgStatus returns the `corosync-cfgtool -s` link status.
+func parseCorosyncCfgStatus(data []byte) (CorosyncCfgStruct, error) {
+	var (
+		cfg  CorosyncCfgStruct
+		link *CorosyncLinkStruct
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		fields := strings.Fields(strings.NewReplacer(":", " ", ",", " ", "=", " ").Replace(line))
+
+		switch {
+		case len(fields) == 0:
+			continue
+		case strings.HasPrefix(line, "Local node ID"):
+			cfg.LocalNodeID = fields[3]
+			if len(fields) > 5 && fields[4] == "transport" {
+				cfg.Transport = fields[5]
+			}
+		case strings.HasPrefix(line, "LINK ID"), strings.HasPrefix(line, "RING ID"):
+			link = cfg.link(fields[2])
+			if len(fields) > 3 {
+				link.Type = fields[3]
+			}
+		case link == nil:
+			continue
+		case (fields[0] == "addr" || fields[0] == "id") && len(fields) > 1:
+			// Keep the colons of IPv6 addresses.
+			link.Address = strings.TrimSpace(line[strings.Index(line, "=")+1:])
+		case fields[0] == "status" && len(fields) > 1:
+			link.Status = strings.Join(fields[1:], " ")
+		case fields[0] == "nodeid" && len(fields) > 2:
+			if stringInSlice("localhost", fields) || fields[1] == cfg.LocalNodeID {
+				continue
+			}
+
+			node := link.node(fields[1])
+			node.Enabled = !stringInSlice("disabled", fields)
+			node.Connected = stringInSlice("connected", fields)
+
+			// corosync 3.0 reports "link enabled:1 link connected:1".
+			for idx := 2; idx < len(fields)-1; idx++ {
+				switch fields[idx] {
+				case "enabled":
+					node.Enabled = fields[idx+1] == "1"
+				case "connected":
+					node.Connected = fields[idx+1] == "1"
+				}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// parseCorosyncCfgNodes merges the `corosync-cfgtool -n` knet node status.
+func parseCorosyncCfgNodes(data []byte, cfg *CorosyncCfgStruct) error {
+	var nodeID string
+
+	cfg.Reachable = make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		fields := strings.Fields(strings.Replace(scanner.Text(), ":", " ", -1))
+		if len(fields) < 3 {
+			continue
+		}
+
+		switch fields[0] {
+		case "nodeid":
+			nodeID = fields[1]
+			cfg.Reachable[nodeID] = fields[2] == "reachable"
+		case "LINK":
+			if nodeID == "" {
+				continue
+			}
+
+			node := cfg.link(fields[1]).node(nodeID)
+			node.Enabled = stringInSlice("enabled", fields)
+			node.Connected = stringInSlice("connected", fields)
+
+			for idx := range fields[:len(fields)-1] {
+				if fields[idx] == "mtu" {
+					node.MTU = parseFloat(fields[idx+1])
+				}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	return nil
+}
+
+// getCorosyncCfgtoolInfo returns corosync-cfgtool information
+func (c *corosyncCfgtoolCollector) getCorosyncCfgtoolInfo(ch chan<- prometheus.Metric) error {
+	outBytes, err := corosyncCfgtoolExec("-s")
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	cfg, err := parseCorosyncCfgStatus(outBytes)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	// Only knet reports the per node enabled, connected and MTU details.
+	if cfg.Transport == "knet" {
+		outBytes, err = corosyncCfgtoolExec("-n")
+		if err != nil {
+			log.Errorln(err)
+			return err
+		}
+
+		err = parseCorosyncCfgNodes(outBytes, &cfg)
+		if err != nil {
+			log.Errorln(err)
+			return err
+		}
+	}
+
+	c.exposeCorosyncCfg(ch, cfg)
+
+	return nil
+}
+
+// expose corosync link metrics
+func (c *corosyncCfgtoolCollector) exposeCorosyncCfg(ch chan<- prometheus.Metric, cfg CorosyncCfgStruct) {
+	for idx := range cfg.Links {
+		link := &cfg.Links[idx]
+
+		ch <- prometheus.MustNewConstMetric(c.corosyncLinkInfo,
+			prometheus.GaugeValue, 1.0, link.LinkID, link.Type, link.Address)
+
+		if link.fault() {
+			ch <- prometheus.MustNewConstMetric(c.corosyncLinkFault,
+				prometheus.GaugeValue, 1.0, link.LinkID)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.corosyncLinkFault,
+				prometheus.GaugeValue, 0.0, link.LinkID)
+		}
+
+		for _, node := range link.Nodes {
+			if node.Connected {
+				ch <- prometheus.MustNewConstMetric(c.corosyncLinkConnected,
+					prometheus.GaugeValue, 1.0, link.LinkID, node.NodeID)
+			} else {
+				ch <- prometheus.MustNewConstMetric(c.corosyncLinkConnected,
+					prometheus.GaugeValue, 0.0, link.LinkID, node.NodeID)
+			}
+
+			if node.Enabled {
+				ch <- prometheus.MustNewConstMetric(c.corosyncLinkEnabled,
+					prometheus.GaugeValue, 1.0, link.LinkID, node.NodeID)
+			} else {
+				ch <- prometheus.MustNewConstMetric(c.corosyncLinkEnabled,
+					prometheus.GaugeValue, 0.0, link.LinkID, node.NodeID)
+			}
+
+			if node.MTU > 0 {
+				ch <- prometheus.MustNewConstMetric(c.corosyncLinkMTU,
+					prometheus.GaugeValue, node.MTU, link.LinkID, node.NodeID)
+			}
+		}
+	}
+
+	for nodeID, reachable := range cfg.Reachable {
+		if reachable {
+			ch <- prometheus.MustNewConstMetric(c.corosyncNodeReachable,
+				prometheus.GaugeValue, 1.0, nodeID)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.corosyncNodeReachable,
+				prometheus.GaugeValue, 0.0, nodeID)
+		}
+	}
+}
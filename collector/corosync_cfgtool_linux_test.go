@@ -0,0 +1,88 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testCorosyncCfgtoolStatus = "fixtures/corosync_cfgtool_status.txt"
+	testCorosyncCfgtoolNodes  = "fixtures/corosync_cfgtool_nodes.txt"
+	testCorosyncCfgtoolRings  = "fixtures/corosync_cfgtool_rings.txt"
+)
+
+func TestParseCorosyncCfgKnet(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCorosyncCfgtoolStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCorosyncCfgStatus(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dataStr.Transport != "knet" || len(dataStr.Links) != 2 {
+		t.Fatalf("transport/links: %v/%v!=knet/2", dataStr.Transport,
+			len(dataStr.Links))
+	}
+
+	if dataStr.Links[0].fault() || !dataStr.Links[1].fault() {
+		t.Fatalf("link faults: %v/%v!=false/true", dataStr.Links[0].fault(),
+			dataStr.Links[1].fault())
+	}
+
+	dataByte, err = ioutil.ReadFile(testCorosyncCfgtoolNodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = parseCorosyncCfgNodes(dataByte, &dataStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := dataStr.link("1").node("3")
+	if !node.Enabled || node.Connected || node.MTU != 469 {
+		t.Fatalf("link 1 node 3 enabled/connected/mtu: %v/%v/%v!=true/false/469",
+			node.Enabled, node.Connected, node.MTU)
+	}
+
+	if !dataStr.Reachable["3"] {
+		t.Fatalf("node 3 reachable: %v!=true", dataStr.Reachable["3"])
+	}
+}
+
+func TestParseCorosyncCfgRings(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCorosyncCfgtoolRings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCorosyncCfgStatus(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dataStr.Links) != 2 || dataStr.Links[0].Address != "192.168.1.11" {
+		t.Fatalf("rings: %v", dataStr.Links)
+	}
+
+	if dataStr.Links[0].fault() || !dataStr.Links[1].fault() {
+		t.Fatalf("ring faults: %v/%v!=false/true", dataStr.Links[0].fault(),
+			dataStr.Links[1].fault())
+	}
+}
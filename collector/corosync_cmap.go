@@ -0,0 +1,184 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// corosyncTotemStat describes how a stats.srp cmap key is exported. The
+// value is multiplied by scale, to convert milliseconds to seconds.
+type corosyncTotemStat struct {
+	desc  *typedDesc
+	scale float64
+}
+
+type corosyncCmapCollector struct {
+	totemStats             map[string]corosyncTotemStat
+	knetLinkLatencyAverage *prometheus.Desc
+	knetLinkLatencyMin     *prometheus.Desc
+	knetLinkLatencyMax     *prometheus.Desc
+	knetLinkPackets        *prometheus.Desc
+	knetLinkBytes          *prometheus.Desc
+	knetLinkErrors         *prometheus.Desc
+	knetLinkRetries        *prometheus.Desc
+	knetLinkDown           *prometheus.Desc
+	knetLinkUp             *prometheus.Desc
+}
+
+func init() {
+	registerCollector("corosync_cmap", defaultEnabled, NewCorosyncCmapCollector)
+}
+
+// newTotemStat returns a corosyncTotemStat for a totem statistic.
+func newTotemStat(name, help string, valueType prometheus.ValueType, scale float64) corosyncTotemStat {
+	return corosyncTotemStat{
+		desc: &typedDesc{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(namespace, "corosync_totem", name),
+				help, nil, nil,
+			),
+			valueType: valueType,
+		},
+		scale: scale,
+	}
+}
+
+// NewCorosyncCmapCollector returns a new Collector exposing corosync runtime statistics.
+func NewCorosyncCmapCollector() (Collector, error) {
+	return &corosyncCmapCollector{
+		totemStats: map[string]corosyncTotemStat{
+			"mtt_rx_token": newTotemStat("mean_token_rotation_time_seconds",
+				"Mean token rotation time in seconds.", prometheus.GaugeValue, 0.001),
+			"time_since_token_last_received": newTotemStat("time_since_token_last_received_seconds",
+				"Time since the token was last received in seconds.", prometheus.GaugeValue, 0.001),
+			"avg_token_workload": newTotemStat("average_token_workload",
+				"Average token workload.", prometheus.GaugeValue, 1),
+			"avg_backlog_calc": newTotemStat("average_backlog",
+				"Average backlog of messages waiting for the token.", prometheus.GaugeValue, 1),
+			"continuous_gather": newTotemStat("continuous_gather",
+				"Number of consecutive gather states entered.", prometheus.GaugeValue, 1),
+			"continuous_sendmsg_failures": newTotemStat("continuous_sendmsg_failures",
+				"Number of consecutive message send failures.", prometheus.GaugeValue, 1),
+			"firewall_enabled_or_nic_failure": newTotemStat("firewall_enabled_or_nic_failure",
+				"Whether a firewall or a network interface failure blocks totem.", prometheus.GaugeValue, 1),
+			"orf_token_tx": newTotemStat("orf_token_sent_total",
+				"Number of ORF tokens sent.", prometheus.CounterValue, 1),
+			"orf_token_rx": newTotemStat("orf_token_received_total",
+				"Number of ORF tokens received.", prometheus.CounterValue, 1),
+			"mcast_tx": newTotemStat("messages_sent_total",
+				"Number of multicast messages sent.", prometheus.CounterValue, 1),
+			"mcast_rx": newTotemStat("messages_received_total",
+				"Number of multicast messages received.", prometheus.CounterValue, 1),
+			"mcast_retx": newTotemStat("messages_retransmitted_total",
+				"Number of multicast messages retransmitted.", prometheus.CounterValue, 1),
+			"rx_msg_dropped": newTotemStat("messages_dropped_total",
+				"Number of received messages dropped.", prometheus.CounterValue, 1),
+			"memb_merge_detect_tx": newTotemStat("merge_detect_sent_total",
+				"Number of merge detect messages sent.", prometheus.CounterValue, 1),
+			"memb_merge_detect_rx": newTotemStat("merge_detect_received_total",
+				"Number of merge detect messages received.", prometheus.CounterValue, 1),
+			"memb_join_tx": newTotemStat("join_sent_total",
+				"Number of join messages sent.", prometheus.CounterValue, 1),
+			"memb_join_rx": newTotemStat("join_received_total",
+				"Number of join messages received.", prometheus.CounterValue, 1),
+			"memb_commit_token_tx": newTotemStat("commit_token_sent_total",
+				"Number of commit tokens sent.", prometheus.CounterValue, 1),
+			"memb_commit_token_rx": newTotemStat("commit_token_received_total",
+				"Number of commit tokens received.", prometheus.CounterValue, 1),
+			"token_hold_cancel_tx": newTotemStat("token_hold_cancel_sent_total",
+				"Number of token hold cancel messages sent.", prometheus.CounterValue, 1),
+			"token_hold_cancel_rx": newTotemStat("token_hold_cancel_received_total",
+				"Number of token hold cancel messages received.", prometheus.CounterValue, 1),
+			"operational_entered": newTotemStat("operational_entered_total",
+				"Number of times the operational state was entered.", prometheus.CounterValue, 1),
+			"operational_token_lost": newTotemStat("operational_token_lost_total",
+				"Number of tokens lost in the operational state.", prometheus.CounterValue, 1),
+			"gather_entered": newTotemStat("gather_entered_total",
+				"Number of times the gather state was entered.", prometheus.CounterValue, 1),
+			"gather_token_lost": newTotemStat("gather_token_lost_total",
+				"Number of tokens lost in the gather state.", prometheus.CounterValue, 1),
+			"commit_entered": newTotemStat("commit_entered_total",
+				"Number of times the commit state was entered.", prometheus.CounterValue, 1),
+			"commit_token_lost": newTotemStat("commit_token_lost_total",
+				"Number of tokens lost in the commit state.", prometheus.CounterValue, 1),
+			"recovery_entered": newTotemStat("recovery_entered_total",
+				"Number of times the recovery state was entered.", prometheus.CounterValue, 1),
+			"recovery_token_lost": newTotemStat("recovery_token_lost_total",
+				"Number of tokens lost in the recovery state.", prometheus.CounterValue, 1),
+			"consensus_timeouts": newTotemStat("consensus_timeouts_total",
+				"Number of consensus timeouts.", prometheus.CounterValue, 1),
+		},
+		knetLinkLatencyAverage: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_knet_link", "latency_average_seconds"),
+			"Average latency of the knet link to the node in seconds.",
+			[]string{"node_id", "link_id"}, nil,
+		),
+		knetLinkLatencyMin: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_knet_link", "latency_min_seconds"),
+			"Minimum latency of the knet link to the node in seconds.",
+			[]string{"node_id", "link_id"}, nil,
+		),
+		knetLinkLatencyMax: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_knet_link", "latency_max_seconds"),
+			"Maximum latency of the knet link to the node in seconds.",
+			[]string{"node_id", "link_id"}, nil,
+		),
+		knetLinkPackets: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_knet_link", "packets_total"),
+			"Number of packets on the knet link to the node by direction and packet type.",
+			[]string{"node_id", "link_id", "direction", "type"}, nil,
+		),
+		knetLinkBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_knet_link", "bytes_total"),
+			"Number of bytes on the knet link to the node by direction and packet type.",
+			[]string{"node_id", "link_id", "direction", "type"}, nil,
+		),
+		knetLinkErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_knet_link", "errors_total"),
+			"Number of send errors on the knet link to the node by packet type.",
+			[]string{"node_id", "link_id", "type"}, nil,
+		),
+		knetLinkRetries: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_knet_link", "retries_total"),
+			"Number of send retries on the knet link to the node by packet type.",
+			[]string{"node_id", "link_id", "type"}, nil,
+		),
+		knetLinkDown: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_knet_link", "down_total"),
+			"Number of times the knet link to the node went down.",
+			[]string{"node_id", "link_id"}, nil,
+		),
+		knetLinkUp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_knet_link", "up_total"),
+			"Number of times the knet link to the node came up.",
+			[]string{"node_id", "link_id"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*corosyncCmapCollector).getCorosyncCmapInfo to get the
+// corosync runtime statistics.
+func (c *corosyncCmapCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getCorosyncCmapInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get corosync cmap statistics: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+var (
+	// corosyncCmapLine matches a `key (type) = value` line.
+	corosyncCmapLine = regexp.MustCompile(`^(\S+) \((\w+)\) = (.*)$`)
+	// corosyncKnetLinkKey matches a stats.knet.nodeX.linkY.field key.
+	corosyncKnetLinkKey = regexp.MustCompile(`^stats\.knet\.node(\d+)\.link(\d+)\.(\w+)$`)
+	// corosyncKnetCounter matches the rx/tx packets, bytes, errors and
+	// retries counters of a knet link, e.g. tx_data_packets.
+	corosyncKnetCounter = regexp.MustCompile(`^(rx|tx)_(\w+)_(packets|bytes|errors|retries)$`)
+)
+
+// execute corosync-cmapctl utility.
+func corosyncCmapctlExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*corosyncCmapctlPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *corosyncCmapctlPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// parseCorosyncCmap returns the numeric cmap values by key. String values
+// are skipped.
+func parseCorosyncCmap(data []byte) (map[string]float64, error) {
+	cmap := make(map[string]float64)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		match := corosyncCmapLine.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil || match[2] == "str" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(match[3], 64)
+		if err != nil {
+			continue
+		}
+
+		cmap[match[1]] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return cmap, err
+	}
+
+	return cmap, nil
+}
+
+// getCorosyncCmapInfo returns corosync-cmapctl statistics
+func (c *corosyncCmapCollector) getCorosyncCmapInfo(ch chan<- prometheus.Metric) error {
+	outBytes, err := corosyncCmapctlExec("-m", "stats")
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	cmap, err := parseCorosyncCmap(outBytes)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeTotemStats(ch, cmap)
+	c.exposeKnetLinkStats(ch, cmap)
+
+	return nil
+}
+
+// expose totem statistics metrics
+func (c *corosyncCmapCollector) exposeTotemStats(ch chan<- prometheus.Metric, cmap map[string]float64) {
+	for key, stat := range c.totemStats {
+		value, ok := cmap["stats.srp."+key]
+		if !ok {
+			continue
+		}
+
+		ch <- stat.desc.mustNewConstMetric(value * stat.scale)
+	}
+}
+
+// expose knet link statistics metrics
+func (c *corosyncCmapCollector) exposeKnetLinkStats(ch chan<- prometheus.Metric, cmap map[string]float64) {
+	for key, value := range cmap {
+		match := corosyncKnetLinkKey.FindStringSubmatch(key)
+		if match == nil {
+			continue
+		}
+
+		nodeID, linkID, field := match[1], match[2], match[3]
+		prefix := "stats.knet.node" + nodeID + ".link" + linkID + "."
+
+		switch field {
+		case "latency_ave", "latency_min", "latency_max":
+			// Latency is in microseconds, and meaningless without samples,
+			// as on the link to the local node.
+			if samples, ok := cmap[prefix+"latency_samples"]; ok && samples == 0 {
+				continue
+			}
+
+			desc := map[string]*prometheus.Desc{
+				"latency_ave": c.knetLinkLatencyAverage,
+				"latency_min": c.knetLinkLatencyMin,
+				"latency_max": c.knetLinkLatencyMax,
+			}[field]
+
+			ch <- prometheus.MustNewConstMetric(desc,
+				prometheus.GaugeValue, value/1e6, nodeID, linkID)
+		case "down_count":
+			ch <- prometheus.MustNewConstMetric(c.knetLinkDown,
+				prometheus.CounterValue, value, nodeID, linkID)
+		case "up_count":
+			ch <- prometheus.MustNewConstMetric(c.knetLinkUp,
+				prometheus.CounterValue, value, nodeID, linkID)
+		default:
+			counter := corosyncKnetCounter.FindStringSubmatch(field)
+			if counter == nil {
+				continue
+			}
+
+			direction, packetType := counter[1], counter[2]
+
+			switch counter[3] {
+			case "packets":
+				ch <- prometheus.MustNewConstMetric(c.knetLinkPackets,
+					prometheus.CounterValue, value, nodeID, linkID, direction, packetType)
+			case "bytes":
+				ch <- prometheus.MustNewConstMetric(c.knetLinkBytes,
+					prometheus.CounterValue, value, nodeID, linkID, direction, packetType)
+			case "errors":
+				ch <- prometheus.MustNewConstMetric(c.knetLinkErrors,
+					prometheus.CounterValue, value, nodeID, linkID, packetType)
+			case "retries":
+				ch <- prometheus.MustNewConstMetric(c.knetLinkRetries,
+					prometheus.CounterValue, value, nodeID, linkID, packetType)
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testCorosyncCmapctlStats = "fixtures/corosync_cmapctl_stats.txt"
+)
+
+func TestParseCorosyncCmap(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCorosyncCmapctlStats)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCorosyncCmap(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dataStr["stats.srp.mtt_rx_token"] != 1821 {
+		t.Fatalf("stats.srp.mtt_rx_token: %v!=1821",
+			dataStr["stats.srp.mtt_rx_token"])
+	}
+
+	if dataStr["stats.knet.node2.link0.latency_max"] != 2830 {
+		t.Fatalf("stats.knet.node2.link0.latency_max: %v!=2830",
+			dataStr["stats.knet.node2.link0.latency_max"])
+	}
+
+	if dataStr["stats.srp.token[0].tx"] != 1591170541215 {
+		t.Fatalf("stats.srp.token[0].tx: %v!=1591170541215",
+			dataStr["stats.srp.token[0].tx"])
+	}
+}
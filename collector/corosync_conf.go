@@ -0,0 +1,100 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type corosyncConfCollector struct {
+	corosyncConfInfo          *prometheus.Desc
+	corosyncConfHash          *prometheus.Desc
+	corosyncConfToken         *prometheus.Desc
+	corosyncConfConsensus     *prometheus.Desc
+	corosyncConfCryptoEnabled *prometheus.Desc
+	corosyncConfNodes         *prometheus.Desc
+	corosyncConfNodeInfo      *prometheus.Desc
+	corosyncConfExpectedVotes *prometheus.Desc
+	corosyncConfTwoNode       *prometheus.Desc
+}
+
+func init() {
+	registerCollector("corosync_conf", defaultEnabled, NewCorosyncConfCollector)
+}
+
+// NewCorosyncConfCollector returns a new Collector exposing the corosync configuration.
+func NewCorosyncConfCollector() (Collector, error) {
+	return &corosyncConfCollector{
+		corosyncConfInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_config", "info"),
+			"A metric with a constant '1' value labeled by cluster name, transport, crypto settings and quorum provider.",
+			[]string{"cluster_name", "transport", "crypto_cipher", "crypto_hash", "quorum_provider"}, nil,
+		),
+		corosyncConfHash: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_config", "hash_info"),
+			"A metric with a constant '1' value labeled by the SHA-256 hash of the corosync configuration file.",
+			[]string{"sha256"}, nil,
+		),
+		corosyncConfToken: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_config", "totem_token_seconds"),
+			"Configured totem token timeout in seconds.",
+			nil, nil,
+		),
+		corosyncConfConsensus: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_config", "totem_consensus_seconds"),
+			"Configured totem consensus timeout in seconds.",
+			nil, nil,
+		),
+		corosyncConfCryptoEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_config", "crypto_enabled"),
+			"Whether totem traffic is encrypted or authenticated.",
+			nil, nil,
+		),
+		corosyncConfNodes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_config", "nodes"),
+			"Number of nodes in the nodelist.",
+			nil, nil,
+		),
+		corosyncConfNodeInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_config", "node_info"),
+			"A metric with a constant '1' value labeled by nodelist node ID, name and ring addresses.",
+			[]string{"node_id", "name", "addresses"}, nil,
+		),
+		corosyncConfExpectedVotes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_config", "quorum_expected_votes"),
+			"Configured number of expected votes.",
+			nil, nil,
+		),
+		corosyncConfTwoNode: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_config", "quorum_two_node"),
+			"Whether the quorum two_node option is enabled.",
+			nil, nil,
+		),
+	}, nil
+}
+
+// Update calls (*corosyncConfCollector).getCorosyncConfInfo to get the
+// corosync configuration metrics.
+func (c *corosyncConfCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getCorosyncConfInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get corosync configuration information: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// CorosyncConfStruct struct stores the corosync.conf information
+type CorosyncConfStruct struct {
+	ClusterName    string
+	Transport      string
+	Token          float64
+	Consensus      float64
+	CryptoCipher   string
+	CryptoHash     string
+	SecAuth        string
+	QuorumProvider string
+	ExpectedVotes  float64
+	TwoNode        bool
+	Nodes          []CorosyncConfNodeStruct
+	SHA256         string
+}
+
+// CorosyncConfNodeStruct struct stores a corosync.conf nodelist node
+type CorosyncConfNodeStruct struct {
+	NodeID    string
+	Name      string
+	Addresses []string
+}
+
+// cryptoEnabled returns whether totem traffic is encrypted or authenticated.
+func (conf CorosyncConfStruct) cryptoEnabled() bool {
+	for _, value := range []string{conf.CryptoCipher, conf.CryptoHash} {
+		if value != "" && value != "none" {
+			return true
+		}
+	}
+
+	return conf.SecAuth == "on"
+}
+
+// parseCorosyncConf returns the corosync.conf configuration.
+func parseCorosyncConf(data []byte) (CorosyncConfStruct, error) {
+	var (
+		conf     CorosyncConfStruct
+		node     *CorosyncConfNodeStruct
+		sections []string
+	)
+
+	conf.SHA256 = fmt.Sprintf("%x", sha256.Sum256(data))
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasSuffix(line, "{") {
+			section := strings.TrimSpace(strings.TrimSuffix(line, "{"))
+			sections = append(sections, section)
+
+			if strings.Join(sections, ".") == "nodelist.node" {
+				node = &CorosyncConfNodeStruct{}
+			}
+
+			continue
+		}
+
+		if line == "}" {
+			if len(sections) == 0 {
+				return conf, fmt.Errorf("unexpected closing brace")
+			}
+
+			if node != nil && strings.Join(sections, ".") == "nodelist.node" {
+				conf.Nodes = append(conf.Nodes, *node)
+				node = nil
+			}
+
+			sections = sections[:len(sections)-1]
+
+			continue
+		}
+
+		keyValue := strings.SplitN(line, ":", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(keyValue[0])
+		value := strings.TrimSpace(keyValue[1])
+
+		switch strings.Join(append(sections, key), ".") {
+		case "totem.cluster_name":
+			conf.ClusterName = value
+		case "totem.transport":
+			conf.Transport = value
+		case "totem.token":
+			conf.Token = parseFloat(value)
+		case "totem.consensus":
+			conf.Consensus = parseFloat(value)
+		case "totem.crypto_cipher":
+			conf.CryptoCipher = value
+		case "totem.crypto_hash":
+			conf.CryptoHash = value
+		case "totem.secauth":
+			conf.SecAuth = value
+		case "quorum.provider":
+			conf.QuorumProvider = value
+		case "quorum.expected_votes":
+			conf.ExpectedVotes = parseFloat(value)
+		case "quorum.two_node":
+			conf.TwoNode = value == "1"
+		case "nodelist.node.nodeid":
+			node.NodeID = value
+		case "nodelist.node.name":
+			node.Name = value
+		default:
+			if node != nil && strings.HasPrefix(key, "ring") &&
+				strings.HasSuffix(key, "_addr") {
+				node.Addresses = append(node.Addresses, value)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return conf, err
+	}
+
+	if len(sections) != 0 {
+		return conf, fmt.Errorf("unclosed section '%s'", strings.Join(sections, "."))
+	}
+
+	return conf, nil
+}
+
+// getCorosyncConfInfo returns corosync.conf information
+func (c *corosyncConfCollector) getCorosyncConfInfo(ch chan<- prometheus.Metric) error {
+	data, err := ioutil.ReadFile(*corosyncConfPath)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	conf, err := parseCorosyncConf(data)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeCorosyncConf(ch, conf)
+
+	return nil
+}
+
+// expose corosync configuration metrics
+func (c *corosyncConfCollector) exposeCorosyncConf(ch chan<- prometheus.Metric, conf CorosyncConfStruct) {
+	ch <- prometheus.MustNewConstMetric(c.corosyncConfInfo,
+		prometheus.GaugeValue, 1.0, conf.ClusterName, conf.Transport,
+		conf.CryptoCipher, conf.CryptoHash, conf.QuorumProvider)
+	ch <- prometheus.MustNewConstMetric(c.corosyncConfHash,
+		prometheus.GaugeValue, 1.0, conf.SHA256)
+
+	// Only export the timeouts set in the file, the defaults depend on the
+	// corosync version.
+	if conf.Token > 0 {
+		ch <- prometheus.MustNewConstMetric(c.corosyncConfToken,
+			prometheus.GaugeValue, conf.Token/1000)
+	}
+
+	if conf.Consensus > 0 {
+		ch <- prometheus.MustNewConstMetric(c.corosyncConfConsensus,
+			prometheus.GaugeValue, conf.Consensus/1000)
+	}
+
+	if conf.cryptoEnabled() {
+		ch <- prometheus.MustNewConstMetric(c.corosyncConfCryptoEnabled,
+			prometheus.GaugeValue, 1.0)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.corosyncConfCryptoEnabled,
+			prometheus.GaugeValue, 0.0)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.corosyncConfNodes,
+		prometheus.GaugeValue, float64(len(conf.Nodes)))
+
+	for _, node := range conf.Nodes {
+		ch <- prometheus.MustNewConstMetric(c.corosyncConfNodeInfo,
+			prometheus.GaugeValue, 1.0, node.NodeID, node.Name,
+			strings.Join(node.Addresses, ","))
+	}
+
+	if conf.ExpectedVotes > 0 {
+		ch <- prometheus.MustNewConstMetric(c.corosyncConfExpectedVotes,
+			prometheus.GaugeValue, conf.ExpectedVotes)
+	}
+
+	if conf.TwoNode {
+		ch <- prometheus.MustNewConstMetric(c.corosyncConfTwoNode,
+			prometheus.GaugeValue, 1.0)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.corosyncConfTwoNode,
+			prometheus.GaugeValue, 0.0)
+	}
+}
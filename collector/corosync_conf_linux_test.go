@@ -0,0 +1,68 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testCorosyncConf = "fixtures/corosync.conf"
+)
+
+func TestParseCorosyncConf(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCorosyncConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCorosyncConf(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dataStr.Transport != "knet" {
+		t.Fatalf("Transport: %v!=knet", dataStr.Transport)
+	}
+
+	if dataStr.Token != 5000 {
+		t.Fatalf("Token: %v!=5000", dataStr.Token)
+	}
+
+	// The consensus timeout is not set in the file, so it is not exported.
+	if dataStr.Consensus != 0 {
+		t.Fatalf("Consensus: %v!=0", dataStr.Consensus)
+	}
+
+	if !dataStr.cryptoEnabled() {
+		t.Fatalf("cryptoEnabled: %v!=true", dataStr.cryptoEnabled())
+	}
+
+	if dataStr.QuorumProvider != "corosync_votequorum" {
+		t.Fatalf("QuorumProvider: %v!=corosync_votequorum", dataStr.QuorumProvider)
+	}
+
+	if len(dataStr.Nodes) != 2 {
+		t.Fatalf("Nodes: %v!=2", len(dataStr.Nodes))
+	}
+
+	if dataStr.Nodes[1].Name != "lustre-mds2" || len(dataStr.Nodes[1].Addresses) != 2 {
+		t.Fatalf("Nodes[1]: %v!=lustre-mds2 with 2 addresses", dataStr.Nodes[1])
+	}
+
+	if _, err := parseCorosyncConf([]byte("totem {\n\ttoken: 3000\n")); err == nil {
+		t.Fatalf("unclosed section: err==nil")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type corosyncQdeviceCollector struct {
+	corosyncQdeviceInfo          *prometheus.Desc
+	corosyncQdeviceConnected     *prometheus.Desc
+	corosyncQdeviceCastVote      *prometheus.Desc
+	corosyncQdeviceHBInterval    *prometheus.Desc
+	corosyncQdeviceExpectedVotes *prometheus.Desc
+}
+
+func init() {
+	registerCollector("corosync_qdevice", defaultDisabled, NewCorosyncQdeviceCollector)
+}
+
+// NewCorosyncQdeviceCollector returns a new Collector exposing the corosync-qdevice status.
+func NewCorosyncQdeviceCollector() (Collector, error) {
+	return &corosyncQdeviceCollector{
+		corosyncQdeviceInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "qdevice", "info"),
+			"A metric with a constant '1' value labeled by qdevice model, algorithm, tie-breaker, cluster name and qnetd host.",
+			[]string{"node_id", "model", "algorithm", "tie_breaker", "cluster_name", "qnetd_host"}, nil,
+		),
+		corosyncQdeviceConnected: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "qdevice", "connected"),
+			"Whether the qdevice is connected to qnetd.",
+			nil, nil,
+		),
+		corosyncQdeviceCastVote: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "qdevice", "cast_vote"),
+			"Whether the qdevice casts its vote to votequorum.",
+			nil, nil,
+		),
+		corosyncQdeviceHBInterval: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "qdevice", "heartbeat_interval_seconds"),
+			"Heartbeat interval of the qdevice, towards votequorum or qnetd.",
+			[]string{"peer"}, nil,
+		),
+		corosyncQdeviceExpectedVotes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "qdevice", "expected_votes"),
+			"Number of expected votes seen by the qdevice.",
+			nil, nil,
+		),
+	}, nil
+}
+
+// Update calls (*corosyncQdeviceCollector).getCorosyncQdeviceInfo to get the
+// corosync-qdevice metrics.
+func (c *corosyncQdeviceCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getCorosyncQdeviceInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get corosync-qdevice information: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// CorosyncQdeviceStruct struct stores the corosync-qdevice-tool status
+type CorosyncQdeviceStruct struct {
+	NodeID            string
+	Model             string
+	HBInterval        float64
+	ExpectedVotes     float64
+	ClusterName       string
+	QnetdHost         string
+	Algorithm         string
+	TieBreaker        string
+	State             string
+	Vote              string
+	NetHBInterval     float64
+	HasNetInformation bool
+}
+
+// execute corosync-qdevice-tool utility.
+func corosyncQdeviceToolExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*corosyncQdeviceToolPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *corosyncQdeviceToolPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// parseParenthesized returns the text between the parentheses of a value,
+// e.g. "cast vote" for "Yes (cast vote)", or "" if there is none.
+func parseParenthesized(value string) string {
+	start := strings.Index(value, "(")
+	end := strings.LastIndex(value, ")")
+	if start < 0 || end < start {
+		return ""
+	}
+
+	return value[start+1 : end]
+}
+
+// parseCorosyncQdevice returns the corosync-qdevice-tool verbose status. The
+// "Qdevice information" section describes the connection to votequorum and
+// the "Qdevice-net information" one the connection to qnetd.
+func parseCorosyncQdevice(data []byte) (CorosyncQdeviceStruct, error) {
+	var qdevice CorosyncQdeviceStruct
+
+	inNet := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "Qdevice-net information" {
+			inNet = true
+			qdevice.HasNetInformation = true
+			continue
+		}
+
+		keyValue := strings.SplitN(line, ":", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(keyValue[1])
+
+		if !inNet {
+			switch keyValue[0] {
+			case "Model":
+				qdevice.Model = value
+			case "Node ID":
+				qdevice.NodeID = value
+			case "HB interval":
+				qdevice.HBInterval, _ = parsePacemakerInterval(value)
+			case "Expected votes":
+				qdevice.ExpectedVotes = parseFloat(value)
+			case "Last poll call":
+				qdevice.Vote = parseParenthesized(value)
+			}
+
+			continue
+		}
+
+		switch keyValue[0] {
+		case "Cluster name":
+			qdevice.ClusterName = value
+		case "QNetd host":
+			qdevice.QnetdHost = value
+		case "HB interval":
+			qdevice.NetHBInterval, _ = parsePacemakerInterval(value)
+		case "Algorithm":
+			qdevice.Algorithm = value
+		case "Tie-breaker":
+			qdevice.TieBreaker = value
+		case "State":
+			qdevice.State = value
+		case "Poll timer running":
+			// The poll timer carries the vote while it is running.
+			if vote := parseParenthesized(value); vote != "" {
+				qdevice.Vote = vote
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return qdevice, err
+	}
+
+	return qdevice, nil
+}
+
+// getCorosyncQdeviceInfo returns corosync-qdevice-tool information
+func (c *corosyncQdeviceCollector) getCorosyncQdeviceInfo(ch chan<- prometheus.Metric) error {
+	outBytes, err := corosyncQdeviceToolExec("-s", "-v")
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	qdevice, err := parseCorosyncQdevice(outBytes)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeCorosyncQdevice(ch, qdevice)
+
+	return nil
+}
+
+// expose corosync qdevice metrics
+func (c *corosyncQdeviceCollector) exposeCorosyncQdevice(ch chan<- prometheus.Metric, qdevice CorosyncQdeviceStruct) {
+	ch <- prometheus.MustNewConstMetric(c.corosyncQdeviceInfo,
+		prometheus.GaugeValue, 1.0, qdevice.NodeID, qdevice.Model,
+		qdevice.Algorithm, qdevice.TieBreaker, qdevice.ClusterName,
+		qdevice.QnetdHost)
+
+	if qdevice.Vote == "cast vote" {
+		ch <- prometheus.MustNewConstMetric(c.corosyncQdeviceCastVote,
+			prometheus.GaugeValue, 1.0)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.corosyncQdeviceCastVote,
+			prometheus.GaugeValue, 0.0)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.corosyncQdeviceHBInterval,
+		prometheus.GaugeValue, qdevice.HBInterval, "votequorum")
+	ch <- prometheus.MustNewConstMetric(c.corosyncQdeviceExpectedVotes,
+		prometheus.GaugeValue, qdevice.ExpectedVotes)
+
+	// Only the net model connects to qnetd.
+	if !qdevice.HasNetInformation {
+		return
+	}
+
+	if qdevice.State == "Connected" {
+		ch <- prometheus.MustNewConstMetric(c.corosyncQdeviceConnected,
+			prometheus.GaugeValue, 1.0)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.corosyncQdeviceConnected,
+			prometheus.GaugeValue, 0.0)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.corosyncQdeviceHBInterval,
+		prometheus.GaugeValue, qdevice.NetHBInterval, "qnetd")
+}
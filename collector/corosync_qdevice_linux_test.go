@@ -0,0 +1,54 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testCorosyncQdeviceTool = "fixtures/corosync_qdevice_tool.txt"
+)
+
+func TestParseCorosyncQdevice(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCorosyncQdeviceTool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCorosyncQdevice(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dataStr.Model != "Net" || dataStr.Algorithm != "Fifty-Fifty split" {
+		t.Fatalf("model/algorithm: %v/%v!=Net/Fifty-Fifty split",
+			dataStr.Model, dataStr.Algorithm)
+	}
+
+	if dataStr.State != "Connected" || dataStr.Vote != "cast vote" {
+		t.Fatalf("state/vote: %v/%v!=Connected/cast vote",
+			dataStr.State, dataStr.Vote)
+	}
+
+	if dataStr.HBInterval != 10 || dataStr.NetHBInterval != 8 {
+		t.Fatalf("heartbeat intervals: %v/%v!=10/8",
+			dataStr.HBInterval, dataStr.NetHBInterval)
+	}
+
+	if dataStr.QnetdHost != "10.0.0.3:5403" {
+		t.Fatalf("qnetd host: %v!=10.0.0.3:5403", dataStr.QnetdHost)
+	}
+}
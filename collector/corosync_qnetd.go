@@ -0,0 +1,77 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type corosyncQnetdCollector struct {
+	corosyncQnetdClusters       *prometheus.Desc
+	corosyncQnetdClusterInfo    *prometheus.Desc
+	corosyncQnetdClusterNodes   *prometheus.Desc
+	corosyncQnetdNodeVote       *prometheus.Desc
+	corosyncQnetdNodeHBInterval *prometheus.Desc
+}
+
+func init() {
+	registerCollector("corosync_qnetd", defaultDisabled, NewCorosyncQnetdCollector)
+}
+
+// NewCorosyncQnetdCollector returns a new Collector exposing the clusters and
+// nodes connected to corosync-qnetd.
+func NewCorosyncQnetdCollector() (Collector, error) {
+	return &corosyncQnetdCollector{
+		corosyncQnetdClusters: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "qnetd", "clusters"),
+			"Number of clusters connected to qnetd.",
+			nil, nil,
+		),
+		corosyncQnetdClusterInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "qnetd", "cluster_info"),
+			"A metric with a constant '1' value labeled by cluster, algorithm and tie-breaker.",
+			[]string{"cluster", "algorithm", "tie_breaker"}, nil,
+		),
+		corosyncQnetdClusterNodes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "qnetd", "cluster_nodes"),
+			"Number of nodes of the cluster connected to qnetd.",
+			[]string{"cluster"}, nil,
+		),
+		corosyncQnetdNodeVote: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "qnetd", "node_vote"),
+			"A metric with a constant '1' value labeled by the vote state, ACK or NACK, qnetd gives to the node.",
+			[]string{"cluster", "node_id", "vote"}, nil,
+		),
+		corosyncQnetdNodeHBInterval: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "qnetd", "node_heartbeat_interval_seconds"),
+			"Heartbeat interval of the node connection to qnetd.",
+			[]string{"cluster", "node_id"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*corosyncQnetdCollector).getCorosyncQnetdInfo to get the
+// corosync-qnetd metrics.
+func (c *corosyncQnetdCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getCorosyncQnetdInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get corosync-qnetd information: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// CorosyncQnetdClusterStruct struct stores a corosync-qnetd-tool cluster
+type CorosyncQnetdClusterStruct struct {
+	Name       string
+	Algorithm  string
+	TieBreaker string
+	Nodes      []CorosyncQnetdNodeStruct
+}
+
+// CorosyncQnetdNodeStruct struct stores a corosync-qnetd-tool cluster node
+type CorosyncQnetdNodeStruct struct {
+	NodeID     string
+	HBInterval float64
+	Vote       string
+}
+
+// execute corosync-qnetd-tool utility.
+func corosyncQnetdToolExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*corosyncQnetdToolPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *corosyncQnetdToolPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// parseCorosyncQnetd returns the clusters of the corosync-qnetd-tool verbose
+// list, e.g. 'Cluster "hacluster":' followed by its 'Node ID 1:' nodes.
+func parseCorosyncQnetd(data []byte) ([]CorosyncQnetdClusterStruct, error) {
+	var clusters []CorosyncQnetdClusterStruct
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "Cluster ") && strings.HasSuffix(line, ":") {
+			name := strings.TrimSuffix(strings.TrimPrefix(line, "Cluster "), ":")
+			clusters = append(clusters, CorosyncQnetdClusterStruct{
+				Name: strings.Trim(name, `"`),
+			})
+			continue
+		}
+
+		if len(clusters) == 0 {
+			continue
+		}
+
+		cluster := &clusters[len(clusters)-1]
+
+		if strings.HasPrefix(line, "Node ID ") && strings.HasSuffix(line, ":") {
+			cluster.Nodes = append(cluster.Nodes, CorosyncQnetdNodeStruct{
+				NodeID: strings.TrimSuffix(strings.TrimPrefix(line, "Node ID "), ":"),
+			})
+			continue
+		}
+
+		keyValue := strings.SplitN(line, ":", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(keyValue[1])
+
+		switch keyValue[0] {
+		case "Algorithm":
+			cluster.Algorithm = value
+		case "Tie-breaker":
+			cluster.TieBreaker = value
+		}
+
+		if len(cluster.Nodes) == 0 {
+			continue
+		}
+
+		node := &cluster.Nodes[len(cluster.Nodes)-1]
+
+		switch keyValue[0] {
+		case "HB interval":
+			node.HBInterval, _ = parsePacemakerInterval(value)
+		case "Vote":
+			// The vote change is followed by the vote state, e.g.
+			// "No change (ACK)".
+			if i := strings.LastIndex(value, "("); i >= 0 {
+				value = strings.TrimSuffix(value[i+1:], ")")
+			}
+			node.Vote = value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return clusters, err
+	}
+
+	return clusters, nil
+}
+
+// getCorosyncQnetdInfo returns corosync-qnetd-tool information
+func (c *corosyncQnetdCollector) getCorosyncQnetdInfo(ch chan<- prometheus.Metric) error {
+	outBytes, err := corosyncQnetdToolExec("-l", "-v")
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	clusters, err := parseCorosyncQnetd(outBytes)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeCorosyncQnetd(ch, clusters)
+
+	return nil
+}
+
+// expose corosync qnetd metrics
+func (c *corosyncQnetdCollector) exposeCorosyncQnetd(ch chan<- prometheus.Metric, clusters []CorosyncQnetdClusterStruct) {
+	ch <- prometheus.MustNewConstMetric(c.corosyncQnetdClusters,
+		prometheus.GaugeValue, float64(len(clusters)))
+
+	for _, cluster := range clusters {
+		ch <- prometheus.MustNewConstMetric(c.corosyncQnetdClusterInfo,
+			prometheus.GaugeValue, 1.0, cluster.Name, cluster.Algorithm,
+			cluster.TieBreaker)
+		ch <- prometheus.MustNewConstMetric(c.corosyncQnetdClusterNodes,
+			prometheus.GaugeValue, float64(len(cluster.Nodes)), cluster.Name)
+
+		for _, node := range cluster.Nodes {
+			ch <- prometheus.MustNewConstMetric(c.corosyncQnetdNodeVote,
+				prometheus.GaugeValue, 1.0, cluster.Name, node.NodeID, node.Vote)
+			ch <- prometheus.MustNewConstMetric(c.corosyncQnetdNodeHBInterval,
+				prometheus.GaugeValue, node.HBInterval, cluster.Name, node.NodeID)
+		}
+	}
+}
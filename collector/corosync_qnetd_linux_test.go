@@ -0,0 +1,58 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testCorosyncQnetdTool = "fixtures/corosync_qnetd_tool.txt"
+)
+
+func TestParseCorosyncQnetd(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCorosyncQnetdTool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clusters, err := parseCorosyncQnetd(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(clusters) != 2 {
+		t.Fatalf("clusters: %v!=2", len(clusters))
+	}
+
+	if clusters[0].Name != "hacluster" || len(clusters[0].Nodes) != 2 {
+		t.Fatalf("cluster '%s' nodes: %v!=2", clusters[0].Name,
+			len(clusters[0].Nodes))
+	}
+
+	node := clusters[0].Nodes[1]
+	if node.NodeID != "2" || node.Vote != "ACK" {
+		t.Fatalf("node '%s' vote: %v!=ACK", node.NodeID, node.Vote)
+	}
+
+	if node.HBInterval != 8 {
+		t.Fatalf("node '%s' heartbeat interval: %v!=8", node.NodeID, node.HBInterval)
+	}
+
+	if clusters[1].Algorithm != "LMS" || clusters[1].Nodes[0].Vote != "NACK" {
+		t.Fatalf("cluster '%s' algorithm/vote: %v/%v!=LMS/NACK", clusters[1].Name,
+			clusters[1].Algorithm, clusters[1].Nodes[0].Vote)
+	}
+}
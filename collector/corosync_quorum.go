@@ -0,0 +1,106 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type corosyncQuorumCollector struct {
+	corosyncQuorumInfo            *prometheus.Desc
+	corosyncQuorumRingSequence    *prometheus.Desc
+	corosyncQuorumNodes           *prometheus.Desc
+	corosyncQuorumQuorate         *prometheus.Desc
+	corosyncQuorumExpectedVotes   *prometheus.Desc
+	corosyncQuorumHighestExpected *prometheus.Desc
+	corosyncQuorumTotalVotes      *prometheus.Desc
+	corosyncQuorumThreshold       *prometheus.Desc
+	corosyncQuorumFlag            *prometheus.Desc
+	corosyncQuorumMemberVotes     *prometheus.Desc
+}
+
+func init() {
+	registerCollector("corosync_quorum", defaultEnabled, NewCorosyncQuorumCollector)
+}
+
+// NewCorosyncQuorumCollector returns a new Collector exposing corosync quorum information.
+func NewCorosyncQuorumCollector() (Collector, error) {
+	return &corosyncQuorumCollector{
+		corosyncQuorumInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_quorum", "info"),
+			"A metric with a constant '1' value labeled by quorum provider and local node ID.",
+			[]string{"provider", "node_id"}, nil,
+		),
+		corosyncQuorumRingSequence: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_quorum", "ring_sequence"),
+			"Sequence number of the corosync ring ID, increased on every membership change.",
+			nil, nil,
+		),
+		corosyncQuorumNodes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_quorum", "nodes"),
+			"Number of nodes in the corosync membership.",
+			nil, nil,
+		),
+		corosyncQuorumQuorate: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_quorum", "quorate"),
+			"Whether the corosync partition is quorate.",
+			nil, nil,
+		),
+		corosyncQuorumExpectedVotes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_quorum", "expected_votes"),
+			"Number of expected votes.",
+			nil, nil,
+		),
+		corosyncQuorumHighestExpected: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_quorum", "highest_expected_votes"),
+			"Highest number of expected votes in the cluster.",
+			nil, nil,
+		),
+		corosyncQuorumTotalVotes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_quorum", "total_votes"),
+			"Number of votes currently present.",
+			nil, nil,
+		),
+		corosyncQuorumThreshold: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_quorum", "threshold_votes"),
+			"Number of votes needed to be quorate.",
+			nil, nil,
+		),
+		corosyncQuorumFlag: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_quorum", "flag"),
+			"Whether a votequorum flag is set.",
+			[]string{"flag"}, nil,
+		),
+		corosyncQuorumMemberVotes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "corosync_quorum", "member_votes"),
+			"Number of votes of a corosync member.",
+			[]string{"node_id", "name", "local"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*corosyncQuorumCollector).getCorosyncQuorumInfo to get the
+// corosync quorum metrics.
+func (c *corosyncQuorumCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getCorosyncQuorumInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get corosync quorum information: %s", err)
+	}
+
+	return nil
+}
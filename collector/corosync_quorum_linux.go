@@ -0,0 +1,262 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// corosyncQuorumFlags maps the votequorum flags to their label values.
+var corosyncQuorumFlags = map[string]string{
+	"2Node":             "two_node",
+	"Quorate":           "quorate",
+	"WaitForAll":        "wait_for_all",
+	"LastManStanding":   "last_man_standing",
+	"AutoTieBreaker":    "auto_tie_breaker",
+	"AllowDowngrade":    "allow_downgrade",
+	"Qdevice":           "qdevice",
+	"QdeviceAlive":      "qdevice_alive",
+	"QdeviceCastVote":   "qdevice_cast_vote",
+	"QdeviceMasterWins": "qdevice_master_wins",
+}
+
+// CorosyncQuorumStruct struct stores the corosync-quorumtool information
+type CorosyncQuorumStruct struct {
+	Provider        string
+	Nodes           float64
+	NodeID          string
+	RingID          string
+	Quorate         bool
+	ExpectedVotes   float64
+	HighestExpected float64
+	TotalVotes      float64
+	Quorum          float64
+	Flags           []string
+	Members         []CorosyncQuorumMemberStruct
+}
+
+// corosyncRingSequence returns the sequence number of a ring ID, e.g.
+// "1/2412" with corosync 2, or the hexadecimal "3.1d" with corosync 3.
+func corosyncRingSequence(ringID string) (float64, error) {
+	if i := strings.Index(ringID, "/"); i >= 0 {
+		return strconv.ParseFloat(ringID[i+1:], 64)
+	}
+
+	if i := strings.Index(ringID, "."); i >= 0 {
+		sequence, err := strconv.ParseUint(ringID[i+1:], 16, 64)
+		return float64(sequence), err
+	}
+
+	return 0, fmt.Errorf("invalid ring ID '%s'", ringID)
+}
+
+// CorosyncQuorumMemberStruct struct stores a corosync-quorumtool member
+type CorosyncQuorumMemberStruct struct {
+	NodeID string
+	Votes  float64
+	Name   string
+	Local  bool
+}
+
+// execute corosync-quorumtool utility.
+func corosyncQuorumtoolExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*corosyncQuorumtoolPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	// Exit status 2 means the partition is not quorate, the output is valid.
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 2 {
+		return out, nil
+	}
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *corosyncQuorumtoolPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// parseFloat returns the leading number of a value, or 0 if there is none.
+func parseFloat(value string) float64 {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0
+	}
+
+	number, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+
+	return number
+}
+
+// parseCorosyncQuorum returns the corosync-quorumtool status.
+func parseCorosyncQuorum(data []byte) (CorosyncQuorumStruct, error) {
+	var quorum CorosyncQuorumStruct
+
+	inMembers := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if inMembers {
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				continue
+			}
+
+			if _, err := strconv.ParseUint(fields[0], 0, 32); err != nil {
+				continue
+			}
+
+			member := CorosyncQuorumMemberStruct{
+				NodeID: fields[0],
+				Votes:  parseFloat(fields[1]),
+			}
+
+			if fields[len(fields)-1] == "(local)" {
+				member.Local = true
+				fields = fields[:len(fields)-1]
+			}
+
+			member.Name = fields[len(fields)-1]
+			quorum.Members = append(quorum.Members, member)
+
+			continue
+		}
+
+		if strings.HasPrefix(line, "Nodeid") {
+			inMembers = true
+			continue
+		}
+
+		keyValue := strings.SplitN(line, ":", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(keyValue[1])
+
+		switch keyValue[0] {
+		case "Quorum provider":
+			quorum.Provider = value
+		case "Nodes":
+			quorum.Nodes = parseFloat(value)
+		case "Node ID":
+			quorum.NodeID = value
+		case "Ring ID":
+			quorum.RingID = value
+		case "Quorate":
+			quorum.Quorate = value == "Yes"
+		case "Expected votes":
+			quorum.ExpectedVotes = parseFloat(value)
+		case "Highest expected":
+			quorum.HighestExpected = parseFloat(value)
+		case "Total votes":
+			quorum.TotalVotes = parseFloat(value)
+		case "Quorum":
+			quorum.Quorum = parseFloat(value)
+		case "Flags":
+			quorum.Flags = strings.Fields(value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return quorum, err
+	}
+
+	return quorum, nil
+}
+
+// getCorosyncQuorumInfo returns corosync-quorumtool information
+func (c *corosyncQuorumCollector) getCorosyncQuorumInfo(ch chan<- prometheus.Metric) error {
+	outBytes, err := corosyncQuorumtoolExec("-s", "-p")
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	quorum, err := parseCorosyncQuorum(outBytes)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeCorosyncQuorum(ch, quorum)
+
+	return nil
+}
+
+// expose corosync quorum metrics
+func (c *corosyncQuorumCollector) exposeCorosyncQuorum(ch chan<- prometheus.Metric, quorum CorosyncQuorumStruct) {
+	ch <- prometheus.MustNewConstMetric(c.corosyncQuorumInfo,
+		prometheus.GaugeValue, 1.0, quorum.Provider, quorum.NodeID)
+
+	if sequence, err := corosyncRingSequence(quorum.RingID); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.corosyncQuorumRingSequence,
+			prometheus.GaugeValue, sequence)
+	} else {
+		log.Warnln(err)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.corosyncQuorumNodes,
+		prometheus.GaugeValue, quorum.Nodes)
+
+	if quorum.Quorate {
+		ch <- prometheus.MustNewConstMetric(c.corosyncQuorumQuorate,
+			prometheus.GaugeValue, 1.0)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.corosyncQuorumQuorate,
+			prometheus.GaugeValue, 0.0)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.corosyncQuorumExpectedVotes,
+		prometheus.GaugeValue, quorum.ExpectedVotes)
+	ch <- prometheus.MustNewConstMetric(c.corosyncQuorumHighestExpected,
+		prometheus.GaugeValue, quorum.HighestExpected)
+	ch <- prometheus.MustNewConstMetric(c.corosyncQuorumTotalVotes,
+		prometheus.GaugeValue, quorum.TotalVotes)
+	ch <- prometheus.MustNewConstMetric(c.corosyncQuorumThreshold,
+		prometheus.GaugeValue, quorum.Quorum)
+
+	for flag, label := range corosyncQuorumFlags {
+		if stringInSlice(flag, quorum.Flags) {
+			ch <- prometheus.MustNewConstMetric(c.corosyncQuorumFlag,
+				prometheus.GaugeValue, 1.0, label)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.corosyncQuorumFlag,
+				prometheus.GaugeValue, 0.0, label)
+		}
+	}
+
+	for _, member := range quorum.Members {
+		ch <- prometheus.MustNewConstMetric(c.corosyncQuorumMemberVotes,
+			prometheus.GaugeValue, member.Votes, member.NodeID, member.Name,
+			strconv.FormatBool(member.Local))
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testCorosyncQuorumtoolOk      = "fixtures/corosync_quorumtool.txt"
+	testCorosyncQuorumtoolBlocked = "fixtures/corosync_quorumtool_blocked.txt"
+)
+
+func TestParseCorosyncQuorum(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCorosyncQuorumtoolOk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCorosyncQuorum(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !dataStr.Quorate || dataStr.ExpectedVotes != 2 || dataStr.Quorum != 1 {
+		t.Fatalf("quorate/expected votes/quorum: %v/%v/%v!=true/2/1",
+			dataStr.Quorate, dataStr.ExpectedVotes, dataStr.Quorum)
+	}
+
+	if !stringInSlice("2Node", dataStr.Flags) || !stringInSlice("WaitForAll", dataStr.Flags) {
+		t.Fatalf("flags: %v doesn't contain 2Node and WaitForAll", dataStr.Flags)
+	}
+
+	if len(dataStr.Members) != 2 {
+		t.Fatalf("members: %v!=2", len(dataStr.Members))
+	}
+
+	if dataStr.Members[0].Name != "lustre-mds1" || !dataStr.Members[0].Local {
+		t.Fatalf("first member: %v!=lustre-mds1 (local)", dataStr.Members[0])
+	}
+}
+
+func TestParseCorosyncQuorumBlocked(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCorosyncQuorumtoolBlocked)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCorosyncQuorum(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dataStr.Quorate || dataStr.TotalVotes != 1 || dataStr.Quorum != 2 {
+		t.Fatalf("quorate/total votes/quorum: %v/%v/%v!=false/1/2",
+			dataStr.Quorate, dataStr.TotalVotes, dataStr.Quorum)
+	}
+
+	if len(dataStr.Flags) != 0 {
+		t.Fatalf("flags: %v is not empty", dataStr.Flags)
+	}
+}
+
+func TestCorosyncRingSequence(t *testing.T) {
+	ringIDs := map[string]float64{
+		"1/2412": 2412,
+		"3.1d":   29,
+	}
+
+	for ringID, expected := range ringIDs {
+		sequence, err := corosyncRingSequence(ringID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if sequence != expected {
+			t.Fatalf("ring ID '%s' sequence: %v!=%v", ringID, sequence, expected)
+		}
+	}
+
+	if _, err := corosyncRingSequence("unknown"); err == nil {
+		t.Fatalf("ring ID 'unknown': err==nil")
+	}
+}
@@ -25,10 +25,12 @@ import (
 var (
 	crmMonElemEnabled = kingpin.Flag("collector.crm_mon.elements-enabled",
 		"Pacemaker `crm_mon` XML elements that will be exported.").Default(
-		"summary,nodes,node_attributes,clones,resources,resources_group,failures,bans").String()
+		"summary,nodes,node_attributes,clones,resources,resources_group,failures,bans,tickets").String()
 )
 
 type crmMonCollector struct {
+	cibQuerier
+
 	crmMonInfo                        *prometheus.Desc
 	crmMonLastUpdate                  *prometheus.Desc
 	crmMonLastChange                  *prometheus.Desc
@@ -52,6 +54,10 @@ type crmMonCollector struct {
 	crmMonNodeExpectedUp              *prometheus.Desc
 	crmMonNodeIsDC                    *prometheus.Desc
 	crmMonNodeResourcesRunning        *prometheus.Desc
+	crmMonNodeResourcesByRole         *prometheus.Desc
+	crmMonNodeResourcesPromoted       *prometheus.Desc
+	crmMonNodesResourcesImbalance     *prometheus.Desc
+	crmMonNodesResourcesStdDev        *prometheus.Desc
 	crmMonNodeAttribute               *prometheus.Desc
 	crmMonResourceActive              *prometheus.Desc
 	crmMonResourceOrphaned            *prometheus.Desc
@@ -59,7 +65,13 @@ type crmMonCollector struct {
 	crmMonResourceManaged             *prometheus.Desc
 	crmMonResourceFailed              *prometheus.Desc
 	crmMonResourceFailureIgnored      *prometheus.Desc
+	crmMonResourceRoleMismatch        *prometheus.Desc
+	crmMonResourcesByAgent            *prometheus.Desc
 	crmMonResourcesGroup              *prometheus.Desc
+	crmMonGroupMembersActive          *prometheus.Desc
+	crmMonGroupFullyActive            *prometheus.Desc
+	crmMonGroupNode                   *prometheus.Desc
+	crmMonGroupSplit                  *prometheus.Desc
 	crmMonResourceGroupActive         *prometheus.Desc
 	crmMonResourceGroupOrphaned       *prometheus.Desc
 	crmMonResourceGroupBlocked        *prometheus.Desc
@@ -76,10 +88,18 @@ type crmMonCollector struct {
 	crmMonResourceCloneFailureIgnored *prometheus.Desc
 	crmMonResourceCloneNumActive      *prometheus.Desc
 	crmMonResourceCloneNumPromoted    *prometheus.Desc
+	crmMonCloneMax                    *prometheus.Desc
+	crmMonCloneNodeMax                *prometheus.Desc
+	crmMonClonePromotedMax            *prometheus.Desc
+	crmMonClonePromotedNodeMax        *prometheus.Desc
+	crmMonCloneInstancesMissing       *prometheus.Desc
+	crmMonClonePromotedMissing        *prometheus.Desc
 	crmMonFailuresCount               *prometheus.Desc
 	crmMonFailureDescription          *prometheus.Desc
 	crmMonBansCount                   *prometheus.Desc
 	crmMonBanDescription              *prometheus.Desc
+	crmMonTicketGranted               *prometheus.Desc
+	crmMonTicketStandby               *prometheus.Desc
 }
 
 func init() {
@@ -205,6 +225,27 @@ func NewCrmMonCollector() (Collector, error) {
 			"Number of resources running on node.",
 			[]string{"name"}, nil,
 		),
+		// Nodes placement metrics computed from the resources section
+		crmMonNodeResourcesByRole: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "node", "resources"),
+			"Number of resource instances placed on node by role.",
+			[]string{"name", "role"}, nil,
+		),
+		crmMonNodeResourcesPromoted: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "node", "resources_promoted"),
+			"Number of promoted resource instances on node.",
+			[]string{"name"}, nil,
+		),
+		crmMonNodesResourcesImbalance: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "nodes", "resources_imbalance_ratio"),
+			"Difference between the most and the least loaded online node resource count divided by the mean, instead of max/min which is +Inf once a node runs nothing.",
+			[]string{"name"}, nil,
+		),
+		crmMonNodesResourcesStdDev: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "nodes", "resources_stddev"),
+			"Standard deviation of the resource count per online node.",
+			[]string{"name"}, nil,
+		),
 		// Node Attributes section metrics
 		crmMonNodeAttribute: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "node", "attribute"),
@@ -215,32 +256,42 @@ func NewCrmMonCollector() (Collector, error) {
 		crmMonResourceActive: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "active"),
 			"Resource is active.",
-			[]string{"id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceOrphaned: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "orphaned"),
 			"Resource is orphaned.",
-			[]string{"id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceBlocked: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "blocked"),
 			"Resource is blocked.",
-			[]string{"id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceManaged: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "managed"),
 			"Resource is managed.",
-			[]string{"id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceFailed: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "failed"),
 			"Resource is failed.",
-			[]string{"id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceFailureIgnored: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "failure_ignored"),
 			"Resource failure ignored.",
-			[]string{"id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
+		),
+		crmMonResourceRoleMismatch: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "resource", "role_mismatch"),
+			"Whether the resource role does not match its target role.",
+			[]string{"resource", "node_name", "role", "target_role"}, nil,
+		),
+		crmMonResourcesByAgent: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "resources"),
+			"Number of resource instances by resource agent, role and node.",
+			[]string{"class", "provider", "type", "role", "node"}, nil,
 		),
 
 		// Node Resources Group metrics
@@ -249,35 +300,55 @@ func NewCrmMonCollector() (Collector, error) {
 			"Number of resources configured in a group.",
 			[]string{"group"}, nil,
 		),
+		crmMonGroupMembersActive: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "group", "members_active"),
+			"Number of active resources in a group.",
+			[]string{"group"}, nil,
+		),
+		crmMonGroupFullyActive: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "group", "fully_active"),
+			"Whether all resources in a group are active.",
+			[]string{"group"}, nil,
+		),
+		crmMonGroupNode: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "group", "node"),
+			"Number of active group resources running on node.",
+			[]string{"group", "node_name"}, nil,
+		),
+		crmMonGroupSplit: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "group", "split"),
+			"Whether the active group resources run on different nodes.",
+			[]string{"group"}, nil,
+		),
 		crmMonResourceGroupActive: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "active"),
 			"Resource is active.",
-			[]string{"id", "group", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "group", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceGroupOrphaned: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "orphaned"),
 			"Resource is orphaned.",
-			[]string{"id", "group", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "group", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceGroupBlocked: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "blocked"),
 			"Resource is blocked.",
-			[]string{"id", "group", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "group", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceGroupManaged: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "managed"),
 			"Resource is managed.",
-			[]string{"id", "group", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "group", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceGroupFailed: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "failed"),
 			"Resource is failed.",
-			[]string{"id", "group", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "group", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceGroupFailureIgnored: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "failure_ignored"),
 			"Resource failure ignored.",
-			[]string{"id", "group", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "group", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 
 		// Node Resources Clone metrics
@@ -296,40 +367,70 @@ func NewCrmMonCollector() (Collector, error) {
 			"Number of promoted clone instances",
 			[]string{"clone_id"}, nil,
 		),
+		crmMonCloneMax: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "clone", "max"),
+			"Number of clone instances expected by clone-max.",
+			[]string{"clone_id"}, nil,
+		),
+		crmMonCloneNodeMax: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "clone", "node_max"),
+			"Number of clone instances allowed per node by clone-node-max.",
+			[]string{"clone_id"}, nil,
+		),
+		crmMonClonePromotedMax: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "clone", "promoted_max"),
+			"Number of promoted clone instances expected by promoted-max.",
+			[]string{"clone_id"}, nil,
+		),
+		crmMonClonePromotedNodeMax: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "clone", "promoted_node_max"),
+			"Number of promoted clone instances allowed per node by promoted-node-max.",
+			[]string{"clone_id"}, nil,
+		),
+		crmMonCloneInstancesMissing: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "clone", "instances_missing"),
+			"Number of clone instances missing to reach clone-max, 0 for clones with target-role Stopped.",
+			[]string{"clone_id"}, nil,
+		),
+		crmMonClonePromotedMissing: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "clone", "promoted_missing"),
+			"Number of promoted clone instances missing to reach promoted-max.",
+			[]string{"clone_id"}, nil,
+		),
 		crmMonResourceClonePromoted: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "promoted"),
 			"Resource is promoted.",
-			[]string{"id", "clone_id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "clone_id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceCloneActive: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "active"),
 			"Resource is active.",
-			[]string{"id", "clone_id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "clone_id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceCloneOrphaned: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "orphaned"),
 			"Resource is orphaned.",
-			[]string{"id", "clone_id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "clone_id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceCloneBlocked: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "blocked"),
 			"Resource is blocked.",
-			[]string{"id", "clone_id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "clone_id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceCloneManaged: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "managed"),
 			"Resource is managed.",
-			[]string{"id", "clone_id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "clone_id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceCloneFailed: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "failed"),
 			"Resource is failed.",
-			[]string{"id", "clone_id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "clone_id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 		crmMonResourceCloneFailureIgnored: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, "resource", "failure_ignored"),
 			"Resource failure ignored.",
-			[]string{"id", "clone_id", "node_name", "resource_agent", "role", "target_role"}, nil,
+			[]string{"id", "clone_id", "node_name", "class", "provider", "type", "role", "target_role"}, nil,
 		),
 
 		// Failures metrics
@@ -354,6 +455,17 @@ func NewCrmMonCollector() (Collector, error) {
 			"Metric with a constant '1' value labeled by the ban description.",
 			[]string{"id", "resource", "node", "weight", "master_only"}, nil,
 		),
+		// Tickets metrics
+		crmMonTicketGranted: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ticket", "granted"),
+			"Whether the ticket is granted to the local site.",
+			[]string{"ticket"}, nil,
+		),
+		crmMonTicketStandby: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "ticket", "standby"),
+			"Whether the ticket is in standby.",
+			[]string{"ticket"}, nil,
+		),
 	}, nil
 }
 
@@ -17,9 +17,11 @@ package collector
 import (
 	"encoding/xml"
 	"fmt"
+	"math"
 	"net/http"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,6 +29,15 @@ import (
 	"github.com/prometheus/common/log"
 )
 
+// Normalized resource roles. Pacemaker 2.1 renamed Master/Slave to
+// Promoted/Unpromoted, and both spellings are still found in the wild.
+const (
+	roleStopped    = "Stopped"
+	roleStarted    = "Started"
+	rolePromoted   = "Promoted"
+	roleUnpromoted = "Unpromoted"
+)
+
 // execute crm_mon utility.
 func crmMonExec(args ...string) ([]byte, error) {
 	cmd := exec.Command(*crmMonPath, args...)
@@ -55,6 +66,60 @@ func parseCrmMonXML(data []byte) (CrmMonStruct, error) {
 	return crmMonOut, nil
 }
 
+// cloneLimitsStruct stores the expected instance counts of a clone.
+type cloneLimitsStruct struct {
+	CloneMax        float64
+	CloneNodeMax    float64
+	PromotedMax     float64
+	PromotedNodeMax float64
+}
+
+// parseCloneLimits returns the clone limits by clone ID, applying the
+// Pacemaker defaults for meta attributes that are not set.
+func parseCloneLimits(cibStruct CibStruct, nodesConfigured float64) map[string]cloneLimitsStruct {
+	cloneLimits := make(map[string]cloneLimitsStruct)
+
+	metaFloat := func(clone CibCloneStruct, defaultValue float64, names ...string) float64 {
+		value, ok := nvSetValue(clone.MetaAttributes, names...)
+		if !ok {
+			return defaultValue
+		}
+
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Warnf("clone '%s': invalid %s value '%s'", clone.ID, names[0], value)
+			return defaultValue
+		}
+
+		return number
+	}
+
+	clones := append([]CibCloneStruct{}, cibStruct.Configuration.Resources.Clone...)
+	clones = append(clones, cibStruct.Configuration.Resources.Master...)
+
+	for _, clone := range clones {
+		cloneLimits[clone.ID] = cloneLimitsStruct{
+			CloneMax:        metaFloat(clone, nodesConfigured, "clone-max"),
+			CloneNodeMax:    metaFloat(clone, 1, "clone-node-max"),
+			PromotedMax:     metaFloat(clone, 1, "promoted-max", "master-max"),
+			PromotedNodeMax: metaFloat(clone, 1, "promoted-node-max", "master-node-max"),
+		}
+	}
+
+	return cloneLimits
+}
+
+// getCloneLimits returns the clone limits from the live CIB, or nil when
+// the CIB can not be queried.
+func (c *crmMonCollector) getCloneLimits(nodesConfigured float64) map[string]cloneLimitsStruct {
+	cibStruct, err := c.getCib()
+	if err != nil {
+		return nil
+	}
+
+	return parseCloneLimits(cibStruct, nodesConfigured)
+}
+
 // getCrmMonInfo returns crm_mon information
 func (c *crmMonCollector) getCrmMonInfo(ch chan<- prometheus.Metric) error {
 	outBytes, err := crmMonExec("-Xr")
@@ -84,6 +149,11 @@ func (c *crmMonCollector) getCrmMonInfo(ch chan<- prometheus.Metric) error {
 		c.exposeNodes(ch, crmMonStruct.Nodes)
 	}
 
+	// Nodes placement metrics, computed from the resources section
+	if stringInSlice("nodes", elemEnabledSlice) {
+		c.exposeNodesPlacement(ch, crmMonStruct)
+	}
+
 	// Node attribute section metrics
 	if stringInSlice("nodes", elemEnabledSlice) {
 		c.exposeNodeAttributes(ch, crmMonStruct.NodeAttributes)
@@ -91,11 +161,14 @@ func (c *crmMonCollector) getCrmMonInfo(ch chan<- prometheus.Metric) error {
 
 	// Resources section metrics
 	if stringInSlice("clones", elemEnabledSlice) {
-		c.exposeResourcesClone(ch, crmMonStruct.Resources)
+		cloneLimits := c.getCloneLimits(crmMonStruct.Summary.NodesConfigured.Number)
+		c.exposeResourcesClone(ch, crmMonStruct.Resources, cloneLimits)
 	}
 
 	if stringInSlice("resources", elemEnabledSlice) {
 		c.exposeResources(ch, crmMonStruct.Resources)
+		c.exposeResourcesRoleMismatch(ch, crmMonStruct.Resources)
+		c.exposeResourcesByAgent(ch, crmMonStruct.Resources)
 	}
 
 	if stringInSlice("resources_group", elemEnabledSlice) {
@@ -110,6 +183,10 @@ func (c *crmMonCollector) getCrmMonInfo(ch chan<- prometheus.Metric) error {
 		c.exposeBans(ch, crmMonStruct)
 	}
 
+	if stringInSlice("tickets", elemEnabledSlice) {
+		c.exposeTickets(ch, crmMonStruct.Tickets)
+	}
+
 	return nil
 }
 
@@ -173,6 +250,142 @@ func stringInSlice(a string, list []string) bool {
 	return false
 }
 
+// normalizeRole maps legacy and current role names to a single spelling.
+func normalizeRole(role string) string {
+	switch strings.ToLower(role) {
+	case "stopped":
+		return roleStopped
+	case "started":
+		return roleStarted
+	case "master", "promoted":
+		return rolePromoted
+	case "slave", "unpromoted":
+		return roleUnpromoted
+	}
+
+	return role
+}
+
+// roleMismatch returns whether a resource role does not satisfy its target
+// role. Both roles are expected to be normalized already. A Promoted target
+// role only allows a clone instance to be promoted, the number of promoted
+// instances is compared with promoted-max by the clone metrics.
+func roleMismatch(role, targetRole string, cloneInstance bool) bool {
+	switch targetRole {
+	case "":
+		return false
+	case roleStarted:
+		// Started allows a promotable instance to be in any active role.
+		return role != roleStarted && role != rolePromoted && role != roleUnpromoted
+	case rolePromoted:
+		if cloneInstance {
+			return role != rolePromoted && role != roleUnpromoted
+		}
+
+		return role != targetRole
+	default:
+		return role != targetRole
+	}
+}
+
+// splitResourceAgent splits a resource agent such as ocf::heartbeat:IPaddr2
+// into its class, provider and type. Only the ocf class has a provider.
+func splitResourceAgent(resourceAgent string) (class, provider, agentType string) {
+	parts := strings.SplitN(strings.Replace(resourceAgent, "::", ":", 1), ":", 3)
+
+	switch len(parts) {
+	case 3:
+		return parts[0], parts[1], parts[2]
+	case 2:
+		return parts[0], "", parts[1]
+	}
+
+	return "", "", resourceAgent
+}
+
+// allResources returns the primitive resources of the tree, including the
+// members of groups and the instances of clones.
+func allResources(resourcesStruct ResourcesStruct) []ResourceStruct {
+	resources := make([]ResourceStruct, 0, len(resourcesStruct.Resource))
+	resources = append(resources, resourcesStruct.Resource...)
+
+	for _, group := range resourcesStruct.Group {
+		resources = append(resources, group.Resource...)
+	}
+
+	for _, clone := range resourcesStruct.Clone {
+		resources = append(resources, cloneResources(clone)...)
+	}
+
+	return resources
+}
+
+// cloneResources returns the instances of a clone, including the members of
+// every instance of a cloned group.
+func cloneResources(clone CloneStruct) []ResourceStruct {
+	resources := make([]ResourceStruct, 0, len(clone.Resource))
+	resources = append(resources, clone.Resource...)
+
+	for _, group := range clone.Group {
+		resources = append(resources, group.Resource...)
+	}
+
+	return resources
+}
+
+// cloneInstancesActive returns the number of active and promoted instances
+// of a clone. An instance of a cloned group counts once, when all of its
+// resources are active or promoted.
+func cloneInstancesActive(clone CloneStruct) (float64, float64) {
+	var numActive, numPromoted float64
+
+	for _, resource := range clone.Resource {
+		if resource.Active {
+			numActive += float64(len(resource.Node))
+		}
+
+		if normalizeRole(resource.Role) == rolePromoted {
+			numPromoted += float64(len(resource.Node))
+		}
+	}
+
+	for _, group := range clone.Group {
+		active := len(group.Resource) > 0
+		promoted := len(group.Resource) > 0
+
+		for _, resource := range group.Resource {
+			active = active && resource.Active
+			promoted = promoted && normalizeRole(resource.Role) == rolePromoted
+		}
+
+		if active {
+			numActive++
+		}
+
+		if promoted {
+			numPromoted++
+		}
+	}
+
+	return numActive, numPromoted
+}
+
+// cloneInstancesMissing returns the number of instances and promoted
+// instances missing to reach the clone limits. Nothing is missing when the
+// clone is intentionally stopped, nor promoted when it is kept unpromoted.
+func cloneInstancesMissing(clone CloneStruct, limits cloneLimitsStruct,
+	numActive, numPromoted float64) (float64, float64) {
+	switch normalizeRole(clone.TargetRole) {
+	case roleStopped:
+		return 0, 0
+	case roleUnpromoted:
+		return math.Max(0, limits.CloneMax-numActive), 0
+	}
+
+	return math.Max(0, limits.CloneMax-numActive),
+		math.Max(0, limits.PromotedMax-numPromoted)
+}
+
 // expose Summary metrics
 func (c *crmMonCollector) exposeSummary(ch chan<- prometheus.Metric, summaryStruct SummaryStruct) error {
 	ch <- prometheus.MustNewConstMetric(c.crmMonInfo, prometheus.GaugeValue,
@@ -340,6 +553,82 @@ func (c *crmMonCollector) exposeNodes(ch chan<- prometheus.Metric, nodesStruct N
 	}
 }
 
+// expose Nodes placement metrics
+func (c *crmMonCollector) exposeNodesPlacement(ch chan<- prometheus.Metric, crmMonStruct CrmMonStruct) {
+	type roleKey struct {
+		node, role string
+	}
+
+	byRole := make(map[roleKey]float64)
+	total := make(map[string]float64)
+	promoted := make(map[string]float64)
+
+	for _, resource := range allResources(crmMonStruct.Resources) {
+		role := normalizeRole(resource.Role)
+
+		for _, node := range resource.Node {
+			byRole[roleKey{node.Name, role}]++
+			total[node.Name]++
+
+			if role == rolePromoted {
+				promoted[node.Name]++
+			}
+		}
+	}
+
+	for key, count := range byRole {
+		ch <- prometheus.MustNewConstMetric(c.crmMonNodeResourcesByRole,
+			prometheus.GaugeValue, count, key.node, key.role)
+	}
+
+	var online []float64
+
+	for _, node := range crmMonStruct.Nodes.Node {
+		ch <- prometheus.MustNewConstMetric(c.crmMonNodeResourcesPromoted,
+			prometheus.GaugeValue, promoted[node.Name], node.Name)
+
+		if node.Online {
+			online = append(online, total[node.Name])
+		}
+	}
+
+	if len(online) == 0 {
+		return
+	}
+
+	ratio, stdDev := placementImbalance(online)
+
+	ch <- prometheus.MustNewConstMetric(c.crmMonNodesResourcesImbalance,
+		prometheus.GaugeValue, ratio, crmMonStruct.Summary.CurrentDC.Name)
+	ch <- prometheus.MustNewConstMetric(c.crmMonNodesResourcesStdDev,
+		prometheus.GaugeValue, stdDev, crmMonStruct.Summary.CurrentDC.Name)
+}
+
+// placementImbalance returns the (max-min)/mean ratio and the population
+// standard deviation of the given per node resource counts. The ratio is 0
+// when the nodes are balanced or run nothing, and reaches the number of nodes
+// when a single node runs everything.
+func placementImbalance(counts []float64) (ratio, stdDev float64) {
+	min, max, sum := math.Inf(1), 0.0, 0.0
+
+	for _, count := range counts {
+		min = math.Min(min, count)
+		max = math.Max(max, count)
+		sum += count
+	}
+
+	mean := sum / float64(len(counts))
+	if mean > 0 {
+		ratio = (max - min) / mean
+	}
+
+	for _, count := range counts {
+		stdDev += (count - mean) * (count - mean)
+	}
+
+	return ratio, math.Sqrt(stdDev / float64(len(counts)))
+}
+
 // expose Node Attribute metrics
 func (c *crmMonCollector) exposeNodeAttributes(ch chan<- prometheus.Metric, nodeAttrStruct NodeAttrStruct) {
 	for _, node := range nodeAttrStruct.Node {
@@ -354,67 +643,154 @@ func (c *crmMonCollector) exposeNodeAttributes(ch chan<- prometheus.Metric, node
 // expose Resources metrics
 func (c *crmMonCollector) exposeResources(ch chan<- prometheus.Metric, resourcesStruct ResourcesStruct) {
 	for _, resource := range resourcesStruct.Resource {
+		class, provider, agentType := splitResourceAgent(resource.ResourceAgent)
+
 		for _, nodeName := range resource.Node {
 			if resource.Active {
 				ch <- prometheus.MustNewConstMetric(c.crmMonResourceActive,
 					prometheus.GaugeValue, 1.0, resource.ID, nodeName.Name,
-					resource.ResourceAgent, resource.Role, resource.TargetRole)
+					class, provider, agentType, resource.Role, resource.TargetRole)
 			} else {
 				ch <- prometheus.MustNewConstMetric(c.crmMonResourceActive,
 					prometheus.GaugeValue, 0.0, resource.ID, nodeName.Name,
-					resource.ResourceAgent, resource.Role, resource.TargetRole)
+					class, provider, agentType, resource.Role, resource.TargetRole)
 			}
 
 			if resource.Orphaned {
 				ch <- prometheus.MustNewConstMetric(c.crmMonResourceOrphaned,
 					prometheus.GaugeValue, 1.0, resource.ID, nodeName.Name,
-					resource.ResourceAgent, resource.Role, resource.TargetRole)
+					class, provider, agentType, resource.Role, resource.TargetRole)
 			} else {
 				ch <- prometheus.MustNewConstMetric(c.crmMonResourceOrphaned,
 					prometheus.GaugeValue, 0.0, resource.ID, nodeName.Name,
-					resource.ResourceAgent, resource.Role, resource.TargetRole)
+					class, provider, agentType, resource.Role, resource.TargetRole)
 			}
 
 			if resource.Blocked {
 				ch <- prometheus.MustNewConstMetric(c.crmMonResourceBlocked,
 					prometheus.GaugeValue, 1.0, resource.ID, nodeName.Name,
-					resource.ResourceAgent, resource.Role, resource.TargetRole)
+					class, provider, agentType, resource.Role, resource.TargetRole)
 			} else {
 				ch <- prometheus.MustNewConstMetric(c.crmMonResourceBlocked,
 					prometheus.GaugeValue, 0.0, resource.ID, nodeName.Name,
-					resource.ResourceAgent, resource.Role, resource.TargetRole)
+					class, provider, agentType, resource.Role, resource.TargetRole)
 			}
 
 			if resource.Managed {
 				ch <- prometheus.MustNewConstMetric(c.crmMonResourceManaged,
 					prometheus.GaugeValue, 1.0, resource.ID, nodeName.Name,
-					resource.ResourceAgent, resource.Role, resource.TargetRole)
+					class, provider, agentType, resource.Role, resource.TargetRole)
 			} else {
 				ch <- prometheus.MustNewConstMetric(c.crmMonResourceManaged,
 					prometheus.GaugeValue, 0.0, resource.ID, nodeName.Name,
-					resource.ResourceAgent, resource.Role, resource.TargetRole)
+					class, provider, agentType, resource.Role, resource.TargetRole)
 			}
 
 			if resource.Failed {
 				ch <- prometheus.MustNewConstMetric(c.crmMonResourceFailed,
 					prometheus.GaugeValue, 1.0, resource.ID, nodeName.Name,
-					resource.ResourceAgent, resource.Role, resource.TargetRole)
+					class, provider, agentType, resource.Role, resource.TargetRole)
 			} else {
 				ch <- prometheus.MustNewConstMetric(c.crmMonResourceFailed,
 					prometheus.GaugeValue, 0.0, resource.ID, nodeName.Name,
-					resource.ResourceAgent, resource.Role, resource.TargetRole)
+					class, provider, agentType, resource.Role, resource.TargetRole)
 			}
 
 			if resource.FailureIgnored {
 				ch <- prometheus.MustNewConstMetric(c.crmMonResourceFailureIgnored,
 					prometheus.GaugeValue, 1.0, resource.ID, nodeName.Name,
-					resource.ResourceAgent, resource.Role, resource.TargetRole)
+					class, provider, agentType, resource.Role, resource.TargetRole)
 			} else {
 				ch <- prometheus.MustNewConstMetric(c.crmMonResourceFailureIgnored,
 					prometheus.GaugeValue, 0.0, resource.ID, nodeName.Name,
-					resource.ResourceAgent, resource.Role, resource.TargetRole)
+					class, provider, agentType, resource.Role, resource.TargetRole)
+			}
+		}
+	}
+}
+
+// expose Resources role mismatch metrics
+func (c *crmMonCollector) exposeResourcesRoleMismatch(ch chan<- prometheus.Metric, resourcesStruct ResourcesStruct) {
+	// Stopped clone instances share the same ID and have no node, so
+	// emit each resource and node pair only once.
+	seen := make(map[string]bool)
+
+	expose := func(resource ResourceStruct, cloneInstance bool) {
+		role := normalizeRole(resource.Role)
+		targetRole := normalizeRole(resource.TargetRole)
+
+		nodeNames := []string{""}
+		if len(resource.Node) > 0 {
+			nodeNames = nodeNames[:0]
+			for _, node := range resource.Node {
+				nodeNames = append(nodeNames, node.Name)
 			}
 		}
+
+		for _, nodeName := range nodeNames {
+			key := resource.ID + "/" + nodeName
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+
+			if roleMismatch(role, targetRole, cloneInstance) {
+				ch <- prometheus.MustNewConstMetric(c.crmMonResourceRoleMismatch,
+					prometheus.GaugeValue, 1.0, resource.ID, nodeName, role,
+					targetRole)
+			} else {
+				ch <- prometheus.MustNewConstMetric(c.crmMonResourceRoleMismatch,
+					prometheus.GaugeValue, 0.0, resource.ID, nodeName, role,
+					targetRole)
+			}
+		}
+	}
+
+	for _, resource := range resourcesStruct.Resource {
+		expose(resource, false)
+	}
+
+	for _, group := range resourcesStruct.Group {
+		for _, resource := range group.Resource {
+			expose(resource, false)
+		}
+	}
+
+	for _, clone := range resourcesStruct.Clone {
+		for _, resource := range cloneResources(clone) {
+			expose(resource, true)
+		}
+	}
+}
+
+// expose Resources aggregated by resource agent metrics
+func (c *crmMonCollector) exposeResourcesByAgent(ch chan<- prometheus.Metric, resourcesStruct ResourcesStruct) {
+	type agentKey struct {
+		class, provider, agentType, role, node string
+	}
+
+	counts := make(map[agentKey]float64)
+
+	for _, resource := range allResources(resourcesStruct) {
+		class, provider, agentType := splitResourceAgent(resource.ResourceAgent)
+		key := agentKey{class, provider, agentType, normalizeRole(resource.Role), ""}
+
+		if len(resource.Node) == 0 {
+			counts[key]++
+			continue
+		}
+
+		for _, node := range resource.Node {
+			key.node = node.Name
+			counts[key]++
+		}
+	}
+
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.crmMonResourcesByAgent,
+			prometheus.GaugeValue, count, key.class, key.provider,
+			key.agentType, key.role, key.node)
 	}
 }
 
@@ -424,77 +800,118 @@ func (c *crmMonCollector) exposeResourcesGroup(ch chan<- prometheus.Metric, reso
 		ch <- prometheus.MustNewConstMetric(c.crmMonResourcesGroup,
 			prometheus.GaugeValue, group.NumberResources, group.ID)
 
+		membersActive := 0
+		groupNodes := make(map[string]float64)
+
 		for _, resource := range group.Resource {
+			if !resource.Active {
+				continue
+			}
+
+			membersActive++
+
+			for _, node := range resource.Node {
+				groupNodes[node.Name]++
+			}
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.crmMonGroupMembersActive,
+			prometheus.GaugeValue, float64(membersActive), group.ID)
+
+		if membersActive > 0 && float64(membersActive) == group.NumberResources {
+			ch <- prometheus.MustNewConstMetric(c.crmMonGroupFullyActive,
+				prometheus.GaugeValue, 1.0, group.ID)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.crmMonGroupFullyActive,
+				prometheus.GaugeValue, 0.0, group.ID)
+		}
+
+		for nodeName, count := range groupNodes {
+			ch <- prometheus.MustNewConstMetric(c.crmMonGroupNode,
+				prometheus.GaugeValue, count, group.ID, nodeName)
+		}
+
+		if len(groupNodes) > 1 {
+			ch <- prometheus.MustNewConstMetric(c.crmMonGroupSplit,
+				prometheus.GaugeValue, 1.0, group.ID)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.crmMonGroupSplit,
+				prometheus.GaugeValue, 0.0, group.ID)
+		}
+
+		for _, resource := range group.Resource {
+			class, provider, agentType := splitResourceAgent(resource.ResourceAgent)
+
 			for _, nodeName := range resource.Node {
 				if resource.Active {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceGroupActive,
 						prometheus.GaugeValue, 1.0, resource.ID, group.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				} else {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceGroupActive,
 						prometheus.GaugeValue, 0.0, resource.ID, group.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				}
 
 				if resource.Orphaned {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceGroupOrphaned,
 						prometheus.GaugeValue, 1.0, resource.ID, group.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				} else {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceGroupOrphaned,
 						prometheus.GaugeValue, 0.0, resource.ID, group.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				}
 
 				if resource.Blocked {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceGroupBlocked,
 						prometheus.GaugeValue, 1.0, resource.ID, group.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				} else {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceGroupBlocked,
 						prometheus.GaugeValue, 0.0, resource.ID, group.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				}
 
 				if resource.Managed {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceGroupManaged,
 						prometheus.GaugeValue, 1.0, resource.ID, group.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				} else {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceGroupManaged,
 						prometheus.GaugeValue, 0.0, resource.ID, group.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				}
 
 				if resource.Failed {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceGroupFailed,
 						prometheus.GaugeValue, 1.0, resource.ID, group.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				} else {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceGroupFailed,
 						prometheus.GaugeValue, 0.0, resource.ID, group.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				}
 
 				if resource.FailureIgnored {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceGroupFailureIgnored,
 						prometheus.GaugeValue, 1.0, resource.ID, group.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				} else {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceGroupFailureIgnored,
 						prometheus.GaugeValue, 0.0, resource.ID, group.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				}
 			}
@@ -503,11 +920,9 @@ func (c *crmMonCollector) exposeResourcesGroup(ch chan<- prometheus.Metric, reso
 }
 
 // expose Resources by Clone metrics
-func (c *crmMonCollector) exposeResourcesClone(ch chan<- prometheus.Metric, resourcesStruct ResourcesStruct) {
+func (c *crmMonCollector) exposeResourcesClone(ch chan<- prometheus.Metric, resourcesStruct ResourcesStruct,
+	cloneLimits map[string]cloneLimitsStruct) {
 	for _, clone := range resourcesStruct.Clone {
-		numActive := 0
-		numPromoted := 0
-
 		if clone.MultiState {
 			ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneMultistate,
 				prometheus.GaugeValue, 1.0, clone.ID)
@@ -516,19 +931,20 @@ func (c *crmMonCollector) exposeResourcesClone(ch chan<- prometheus.Metric, reso
 				prometheus.GaugeValue, 0.0, clone.ID)
 		}
 
-		for _, resource := range clone.Resource {
+		for _, resource := range cloneResources(clone) {
+			class, provider, agentType := splitResourceAgent(resource.ResourceAgent)
+
 			for _, nodeName := range resource.Node {
 				if clone.MultiState {
-					if resource.Role == "Master" {
+					if normalizeRole(resource.Role) == rolePromoted {
 						ch <- prometheus.MustNewConstMetric(c.crmMonResourceClonePromoted,
 							prometheus.GaugeValue, 1.0, resource.ID, clone.ID,
-							nodeName.Name, resource.ResourceAgent, resource.Role,
+							nodeName.Name, class, provider, agentType, resource.Role,
 							resource.TargetRole)
-						numPromoted++
 					} else {
 						ch <- prometheus.MustNewConstMetric(c.crmMonResourceClonePromoted,
 							prometheus.GaugeValue, 0.0, resource.ID, clone.ID,
-							nodeName.Name, resource.ResourceAgent, resource.Role,
+							nodeName.Name, class, provider, agentType, resource.Role,
 							resource.TargetRole)
 					}
 				}
@@ -536,83 +952,109 @@ func (c *crmMonCollector) exposeResourcesClone(ch chan<- prometheus.Metric, reso
 				if resource.Active {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneActive,
 						prometheus.GaugeValue, 1.0, resource.ID, clone.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
-					numActive++
 				} else {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneActive,
 						prometheus.GaugeValue, 0.0, resource.ID, clone.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				}
 
 				if resource.Orphaned {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneOrphaned,
 						prometheus.GaugeValue, 1.0, resource.ID, clone.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				} else {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneOrphaned,
 						prometheus.GaugeValue, 0.0, resource.ID, clone.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				}
 
 				if resource.Blocked {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneBlocked,
 						prometheus.GaugeValue, 1.0, resource.ID, clone.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				} else {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneBlocked,
 						prometheus.GaugeValue, 0.0, resource.ID, clone.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				}
 
 				if resource.Managed {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneManaged,
 						prometheus.GaugeValue, 1.0, resource.ID, clone.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				} else {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneManaged,
 						prometheus.GaugeValue, 0.0, resource.ID, clone.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				}
 
 				if resource.Failed {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneFailed,
 						prometheus.GaugeValue, 1.0, resource.ID, clone.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				} else {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneFailed,
 						prometheus.GaugeValue, 0.0, resource.ID, clone.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				}
 
 				if resource.FailureIgnored {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneFailureIgnored,
 						prometheus.GaugeValue, 1.0, resource.ID, clone.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				} else {
 					ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneFailureIgnored,
 						prometheus.GaugeValue, 0.0, resource.ID, clone.ID,
-						nodeName.Name, resource.ResourceAgent, resource.Role,
+						nodeName.Name, class, provider, agentType, resource.Role,
 						resource.TargetRole)
 				}
 			}
 		}
+
+		numActive, numPromoted := cloneInstancesActive(clone)
+
 		ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneNumActive,
-			prometheus.GaugeValue, float64(numActive), clone.ID)
+			prometheus.GaugeValue, numActive, clone.ID)
 
 		if clone.MultiState {
 			ch <- prometheus.MustNewConstMetric(c.crmMonResourceCloneNumPromoted,
-				prometheus.GaugeValue, float64(numPromoted), clone.ID)
+				prometheus.GaugeValue, numPromoted, clone.ID)
+		}
+
+		limits, ok := cloneLimits[clone.ID]
+		if !ok {
+			continue
+		}
+
+		instancesMissing, promotedMissing := cloneInstancesMissing(clone, limits,
+			numActive, numPromoted)
+
+		ch <- prometheus.MustNewConstMetric(c.crmMonCloneMax,
+			prometheus.GaugeValue, limits.CloneMax, clone.ID)
+		ch <- prometheus.MustNewConstMetric(c.crmMonCloneNodeMax,
+			prometheus.GaugeValue, limits.CloneNodeMax, clone.ID)
+		ch <- prometheus.MustNewConstMetric(c.crmMonCloneInstancesMissing,
+			prometheus.GaugeValue, instancesMissing, clone.ID)
+
+		if clone.MultiState {
+			ch <- prometheus.MustNewConstMetric(c.crmMonClonePromotedMax,
+				prometheus.GaugeValue, limits.PromotedMax, clone.ID)
+			ch <- prometheus.MustNewConstMetric(c.crmMonClonePromotedNodeMax,
+				prometheus.GaugeValue, limits.PromotedNodeMax, clone.ID)
+			ch <- prometheus.MustNewConstMetric(c.crmMonClonePromotedMissing,
+				prometheus.GaugeValue, promotedMissing, clone.ID)
 		}
 	}
 }
@@ -645,3 +1087,24 @@ func (c *crmMonCollector) exposeBans(ch chan<- prometheus.Metric, crmMonStruct C
 			ban.ID, ban.Resource, ban.Node, ban.Weight, ban.MasterOnly)
 	}
 }
+
+// expose tickets metrics
+func (c *crmMonCollector) exposeTickets(ch chan<- prometheus.Metric, tickets TicketsStruct) {
+	for _, ticket := range tickets.Ticket {
+		if ticket.Status == "granted" {
+			ch <- prometheus.MustNewConstMetric(c.crmMonTicketGranted,
+				prometheus.GaugeValue, 1.0, ticket.ID)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.crmMonTicketGranted,
+				prometheus.GaugeValue, 0.0, ticket.ID)
+		}
+
+		if ticket.Standby {
+			ch <- prometheus.MustNewConstMetric(c.crmMonTicketStandby,
+				prometheus.GaugeValue, 1.0, ticket.ID)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.crmMonTicketStandby,
+				prometheus.GaugeValue, 0.0, ticket.ID)
+		}
+	}
+}
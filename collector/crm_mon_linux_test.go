@@ -23,6 +23,8 @@ const (
 	testCrmStatusOk       = "fixtures/crm_status.xml"
 	testCrmStatusDockerOk = "fixtures/crm_status_docker.xml"
 	testCrmStatusFailed   = "fixtures/crm_status_failed.xml"
+	testCrmStatusClone    = "fixtures/crm_status_clone_group.xml"
+	testCib               = "fixtures/cib.xml"
 )
 
 func TestParseCrmMonXML(t *testing.T) {
@@ -59,6 +61,14 @@ func TestParseCrmMonXML(t *testing.T) {
 			}
 		}
 	}
+
+	if len(dataStr.Tickets.Ticket) != 2 {
+		t.Fatalf("tickets: %v!=2", len(dataStr.Tickets.Ticket))
+	}
+	if dataStr.Tickets.Ticket[0].Status != "granted" {
+		t.Fatalf("ticket '%s' status: %v!=granted",
+			dataStr.Tickets.Ticket[0].ID, dataStr.Tickets.Ticket[0].Status)
+	}
 }
 
 func TestParseCrmMonXMLDocker(t *testing.T) {
@@ -94,3 +104,193 @@ func TestParseCrmMonXMLFailed(t *testing.T) {
 			dataStr.Failures.Failure[0].Node)
 	}
 }
+
+func TestRoleMismatch(t *testing.T) {
+	tests := []struct {
+		role       string
+		targetRole string
+		mismatch   bool
+	}{
+		{"Started", "", false},
+		{"Stopped", "Started", true},
+		{"Started", "Started", false},
+		{"Master", "Started", false},
+		{"Slave", "Promoted", true},
+		{"Promoted", "Master", false},
+		{"Unpromoted", "Slave", false},
+		{"Started", "Stopped", true},
+		{"Stopped", "stopped", false},
+	}
+
+	for _, test := range tests {
+		mismatch := roleMismatch(normalizeRole(test.role),
+			normalizeRole(test.targetRole), false)
+		if mismatch != test.mismatch {
+			t.Fatalf("role '%s' target_role '%s' mismatch: %v!=%v",
+				test.role, test.targetRole, mismatch, test.mismatch)
+		}
+	}
+}
+
+func TestRoleMismatchPromotedClone(t *testing.T) {
+	// A promotable clone with promoted-max=1, left with target-role=Master.
+	data := `<crm_mon version="2.0.5"><resources>
+  <clone id="drbd-r0-clone" multi_state="true" unique="false" managed="true" failed="false" failure_ignored="false" target_role="Master">
+    <resource id="drbd-r0" resource_agent="ocf::linbit:drbd" role="Master" target_role="Master" active="true" orphaned="false" blocked="false" managed="true" failed="false" failure_ignored="false" nodes_running_on="1">
+      <node name="lustre-mds1" id="1" cached="true"/>
+    </resource>
+    <resource id="drbd-r0" resource_agent="ocf::linbit:drbd" role="Slave" target_role="Master" active="true" orphaned="false" blocked="false" managed="true" failed="false" failure_ignored="false" nodes_running_on="1">
+      <node name="lustre-mds2" id="2" cached="true"/>
+    </resource>
+  </clone>
+</resources></crm_mon>`
+
+	dataStr, err := parseCrmMonXML([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clone := dataStr.Resources.Clone[0]
+
+	// Both instances satisfy the Promoted target role.
+	for _, resource := range clone.Resource {
+		if roleMismatch(normalizeRole(resource.Role), normalizeRole(resource.TargetRole), true) {
+			t.Fatalf("clone instance role '%s' target_role '%s': mismatch",
+				resource.Role, resource.TargetRole)
+		}
+	}
+
+	// The promoted instances are compared with promoted-max instead.
+	limits := cloneLimitsStruct{2, 1, 1, 1}
+	numActive, numPromoted := cloneInstancesActive(clone)
+
+	if _, promotedMissing := cloneInstancesMissing(clone, limits, numActive, numPromoted); promotedMissing != 0 {
+		t.Fatalf("promoted missing: %v!=0", promotedMissing)
+	}
+
+	clone.Resource[0].Role = "Slave"
+	numActive, numPromoted = cloneInstancesActive(clone)
+
+	if _, promotedMissing := cloneInstancesMissing(clone, limits, numActive, numPromoted); promotedMissing != 1 {
+		t.Fatalf("promoted missing: %v!=1", promotedMissing)
+	}
+}
+
+func TestSplitResourceAgent(t *testing.T) {
+	tests := []struct {
+		resourceAgent string
+		class         string
+		provider      string
+		agentType     string
+	}{
+		{"ocf::heartbeat:IPaddr2", "ocf", "heartbeat", "IPaddr2"},
+		{"ocf:heartbeat:Filesystem", "ocf", "heartbeat", "Filesystem"},
+		{"systemd:cmetricd@ha", "systemd", "", "cmetricd@ha"},
+		{"stonith:fence_cpower", "stonith", "", "fence_cpower"},
+	}
+
+	for _, test := range tests {
+		class, provider, agentType := splitResourceAgent(test.resourceAgent)
+		if class != test.class || provider != test.provider || agentType != test.agentType {
+			t.Fatalf("resource_agent '%s': %s/%s/%s!=%s/%s/%s",
+				test.resourceAgent, class, provider, agentType,
+				test.class, test.provider, test.agentType)
+		}
+	}
+}
+
+func TestPlacementImbalance(t *testing.T) {
+	tests := []struct {
+		counts []float64
+		ratio  float64
+		stdDev float64
+	}{
+		{[]float64{6, 22}, 16.0 / 14.0, 8},
+		{[]float64{4, 4, 4}, 0, 0},
+		{[]float64{0, 0}, 0, 0},
+		{[]float64{0, 10}, 2, 5},
+	}
+
+	for _, test := range tests {
+		ratio, stdDev := placementImbalance(test.counts)
+		if ratio != test.ratio || stdDev != test.stdDev {
+			t.Fatalf("counts %v: ratio %v!=%v, stddev %v!=%v",
+				test.counts, ratio, test.ratio, stdDev, test.stdDev)
+		}
+	}
+}
+
+func TestParseCloneLimits(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCib)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCibXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cloneLimits := parseCloneLimits(dataStr, 2)
+
+	tests := map[string]cloneLimitsStruct{
+		"lustreclient-clone": {2, 1, 1, 1},
+		"ping-lnet-clone":    {3, 1, 1, 1},
+		"mysql-master":       {2, 1, 1, 1},
+		"drbd-r0-clone":      {2, 1, 1, 1},
+	}
+
+	for cloneID, expected := range tests {
+		if cloneLimits[cloneID] != expected {
+			t.Fatalf("clone '%s' limits: %v!=%v", cloneID,
+				cloneLimits[cloneID], expected)
+		}
+	}
+}
+
+func TestCloneInstances(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCrmStatusClone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCrmMonXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	limits := cloneLimitsStruct{2, 1, 1, 1}
+
+	tests := map[string]struct {
+		numActive        float64
+		instancesMissing float64
+	}{
+		// Only one instance of the cloned group has all its resources active.
+		"shared-vg1-clone": {1, 1},
+		// A clone stopped on purpose is not missing any instance.
+		"ping-lnet-clone": {0, 0},
+	}
+
+	for _, clone := range dataStr.Resources.Clone {
+		expected, ok := tests[clone.ID]
+		if !ok {
+			t.Fatalf("unexpected clone '%s'", clone.ID)
+		}
+
+		numActive, numPromoted := cloneInstancesActive(clone)
+		if numActive != expected.numActive || numPromoted != 0 {
+			t.Fatalf("clone '%s' active/promoted: %v/%v!=%v/0", clone.ID,
+				numActive, numPromoted, expected.numActive)
+		}
+
+		instancesMissing, _ := cloneInstancesMissing(clone, limits, numActive, numPromoted)
+		if instancesMissing != expected.instancesMissing {
+			t.Fatalf("clone '%s' instances missing: %v!=%v", clone.ID,
+				instancesMissing, expected.instancesMissing)
+		}
+	}
+
+	if resources := allResources(dataStr.Resources); len(resources) != 6 {
+		t.Fatalf("resources: %v!=6", len(resources))
+	}
+}
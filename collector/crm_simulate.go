@@ -0,0 +1,54 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type crmSimulateCollector struct {
+	crmSimulateQuerier
+
+	crmSimulatePendingActions *prometheus.Desc
+}
+
+func init() {
+	registerCollector("crm_simulate", defaultDisabled, NewCrmSimulateCollector)
+}
+
+// NewCrmSimulateCollector returns a new Collector exposing the scheduler transition summary.
+func NewCrmSimulateCollector() (Collector, error) {
+	return &crmSimulateCollector{
+		crmSimulatePendingActions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "transition", "pending_actions"),
+			"Number of actions the scheduler would take now on the live cluster, by action.",
+			[]string{"action"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*crmSimulateCollector).getCrmSimulateInfo to get the
+// scheduler transition metrics.
+func (c *crmSimulateCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getCrmSimulateInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get crm_simulate information: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// transitionActions are the transition summary actions always exported.
+var transitionActions = []string{"start", "stop", "move", "migrate", "restart",
+	"recover", "promote", "demote", "fence", "shutdown"}
+
+// execute crm_simulate utility.
+func crmSimulateExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*crmSimulatePath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *crmSimulatePath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// crmSimulateQueryStruct struct stores the crm_simulate output queried once
+// and shared by all the collectors of a scrape.
+type crmSimulateQueryStruct struct {
+	once sync.Once
+	out  []byte
+	err  error
+}
+
+// newCrmSimulateQuery returns a crm_simulate query to be shared during a
+// scrape.
+func newCrmSimulateQuery() *crmSimulateQueryStruct {
+	return &crmSimulateQueryStruct{}
+}
+
+// get returns the crm_simulate output, calling crm_simulate on first use only.
+func (q *crmSimulateQueryStruct) get() ([]byte, error) {
+	q.once.Do(func() {
+		q.out, q.err = crmSimulateExec("-Ls")
+	})
+
+	return q.out, q.err
+}
+
+// crmSimulateQuerier is embedded by the collectors reading crm_simulate.
+type crmSimulateQuerier struct {
+	crmSimulateQuery *crmSimulateQueryStruct
+}
+
+// setCrmSimulateQuery implements the crmSimulateQueryCollector interface.
+func (c *crmSimulateQuerier) setCrmSimulateQuery(q *crmSimulateQueryStruct) {
+	c.crmSimulateQuery = q
+}
+
+// getCrmSimulate returns the crm_simulate output shared by the scrape,
+// querying it on its own when the collector is used outside of a
+// PacemakerCollector.
+func (c *crmSimulateQuerier) getCrmSimulate() ([]byte, error) {
+	if c.crmSimulateQuery == nil {
+		c.crmSimulateQuery = newCrmSimulateQuery()
+	}
+
+	return c.crmSimulateQuery.get()
+}
+
+// parseTransitionSummary returns the number of actions by action of the
+// crm_simulate transition summary.
+func parseTransitionSummary(data []byte) (map[string]float64, error) {
+	actions := make(map[string]float64)
+
+	for _, action := range transitionActions {
+		actions[action] = 0
+	}
+
+	inSummary := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "Transition Summary:" {
+			inSummary = true
+			continue
+		}
+
+		if !inSummary {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "* ") {
+			if line == "" {
+				inSummary = false
+			}
+
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		// Leave is printed for resources without actions by older versions.
+		action := strings.ToLower(fields[1])
+		if action != "leave" {
+			actions[action]++
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return actions, err
+	}
+
+	return actions, nil
+}
+
+// getCrmSimulateInfo returns crm_simulate information
+func (c *crmSimulateCollector) getCrmSimulateInfo(ch chan<- prometheus.Metric) error {
+	outBytes, err := c.getCrmSimulate()
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	actions, err := parseTransitionSummary(outBytes)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeTransitionSummary(ch, actions)
+
+	return nil
+}
+
+// expose transition summary metrics
+func (c *crmSimulateCollector) exposeTransitionSummary(ch chan<- prometheus.Metric, actions map[string]float64) {
+	for action, count := range actions {
+		ch <- prometheus.MustNewConstMetric(c.crmSimulatePendingActions,
+			prometheus.GaugeValue, count, action)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testCrmSimulate = "fixtures/crm_simulate.txt"
+)
+
+func TestParseTransitionSummary(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCrmSimulate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseTransitionSummary(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]float64{
+		"start":   1,
+		"stop":    1,
+		"move":    2,
+		"promote": 1,
+		"demote":  1,
+		"fence":   1,
+		"restart": 1,
+		"recover": 0,
+	}
+
+	for action, count := range expected {
+		if dataStr[action] != count {
+			t.Fatalf("%s: %v!=%v", action, dataStr[action], count)
+		}
+	}
+
+	if len(dataStr) != len(transitionActions) {
+		t.Fatalf("actions: %v!=%v", len(dataStr), len(transitionActions))
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type crmVerifyCollector struct {
+	crmVerifyErrors   *prometheus.Desc
+	crmVerifyWarnings *prometheus.Desc
+	crmVerifyMessage  *prometheus.Desc
+}
+
+func init() {
+	registerCollector("crm_verify", defaultDisabled, NewCrmVerifyCollector)
+}
+
+// NewCrmVerifyCollector returns a new Collector exposing the configuration validation.
+func NewCrmVerifyCollector() (Collector, error) {
+	return &crmVerifyCollector{
+		crmVerifyErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "config", "errors"),
+			"Number of distinct configuration errors reported by crm_verify.",
+			nil, nil,
+		),
+		crmVerifyWarnings: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "config", "warnings"),
+			"Number of distinct configuration warnings reported by crm_verify.",
+			nil, nil,
+		),
+		crmVerifyMessage: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "config", "message"),
+			"A metric with a constant '1' value labeled by crm_verify message level and text.",
+			[]string{"level", "message"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*crmVerifyCollector).getCrmVerifyInfo to get the
+// configuration validation metrics.
+func (c *crmVerifyCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getCrmVerifyInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get crm_verify information: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// crm_verify exit statuses.
+const (
+	// crmVerifyExitConfig means the configuration has errors or warnings
+	// since Pacemaker 2.0, crmVerifyExitGeneric before.
+	crmVerifyExitConfig  = 78
+	crmVerifyExitGeneric = 201
+	// crmVerifyExitUsage means an option is not supported.
+	crmVerifyExitUsage = 64
+)
+
+var (
+	// crmVerifyLine matches an error or warning of crm_verify. Before
+	// Pacemaker 2.0 the message is prefixed by the function name and a tab.
+	crmVerifyLine = regexp.MustCompile(`^\s*(error|warning):\s*(?:\w+:\t)?\s*(.*)$`)
+	// errCrmVerifyUsage is returned when crm_verify does not support an option.
+	errCrmVerifyUsage = errors.New("crm_verify: unsupported option")
+)
+
+// CrmVerifyMessageStruct struct stores a crm_verify message
+type CrmVerifyMessageStruct struct {
+	Level   string
+	Message string
+}
+
+// execute crm_verify utility. A configuration with errors or warnings makes
+// crm_verify exit with an error, but its output is still valid.
+func crmVerifyExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*crmVerifyPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.CombinedOutput()
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		switch exitErr.ExitCode() {
+		case crmVerifyExitConfig, crmVerifyExitGeneric:
+			return out, nil
+		case crmVerifyExitUsage:
+			return out, errCrmVerifyUsage
+		}
+	}
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *crmVerifyPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// parseCrmVerifyLines returns the distinct errors and warnings of crm_verify
+// text lines.
+func parseCrmVerifyLines(lines []string) []CrmVerifyMessageStruct {
+	var messages []CrmVerifyMessageStruct
+
+	seen := make(map[CrmVerifyMessageStruct]bool)
+
+	for _, line := range lines {
+		match := crmVerifyLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		message := CrmVerifyMessageStruct{Level: match[1], Message: strings.TrimSpace(match[2])}
+		if seen[message] {
+			continue
+		}
+
+		seen[message] = true
+		messages = append(messages, message)
+	}
+
+	return messages
+}
+
+// parseCrmVerify returns the distinct errors and warnings of the crm_verify
+// text output.
+func parseCrmVerify(data []byte) ([]CrmVerifyMessageStruct, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return nil, err
+	}
+
+	return parseCrmVerifyLines(lines), nil
+}
+
+// parseCrmVerifyXML returns the distinct errors and warnings of the
+// crm_verify XML output.
+func parseCrmVerifyXML(data []byte) ([]CrmVerifyMessageStruct, error) {
+	var crmVerifyOut CrmVerifyStruct
+
+	err := xml.Unmarshal(data, &crmVerifyOut)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only a valid configuration, or one with errors or warnings, is checked.
+	switch crmVerifyOut.Status.Code {
+	case "0", fmt.Sprint(crmVerifyExitConfig):
+	default:
+		return nil, fmt.Errorf("crm_verify failed with status %s: %s",
+			crmVerifyOut.Status.Code, crmVerifyOut.Status.Message)
+	}
+
+	return parseCrmVerifyLines(crmVerifyOut.Status.Errors), nil
+}
+
+// getCrmVerifyInfo returns crm_verify information. The XML output is used
+// when crm_verify supports it, Pacemaker 2.0 and older print text.
+func (c *crmVerifyCollector) getCrmVerifyInfo(ch chan<- prometheus.Metric) error {
+	var messages []CrmVerifyMessageStruct
+
+	outBytes, err := crmVerifyExec("--output-as", "xml", "-L", "-V")
+	if err == errCrmVerifyUsage {
+		outBytes, err = crmVerifyExec("-L", "-V")
+		if err != nil {
+			log.Errorln(err)
+			return err
+		}
+
+		messages, err = parseCrmVerify(outBytes)
+	} else if err == nil {
+		messages, err = parseCrmVerifyXML(outBytes)
+	}
+
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeCrmVerify(ch, messages)
+
+	return nil
+}
+
+// expose crm_verify metrics
+func (c *crmVerifyCollector) exposeCrmVerify(ch chan<- prometheus.Metric, messages []CrmVerifyMessageStruct) {
+	counts := map[string]float64{"error": 0, "warning": 0}
+
+	for _, message := range messages {
+		counts[message.Level]++
+
+		ch <- prometheus.MustNewConstMetric(c.crmVerifyMessage,
+			prometheus.GaugeValue, 1.0, message.Level, message.Message)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.crmVerifyErrors,
+		prometheus.GaugeValue, counts["error"])
+	ch <- prometheus.MustNewConstMetric(c.crmVerifyWarnings,
+		prometheus.GaugeValue, counts["warning"])
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testCrmVerify    = "fixtures/crm_verify.txt"
+	testCrmVerifyXML = "fixtures/crm_verify.xml"
+)
+
+func TestParseCrmVerify(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCrmVerify)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCrmVerify(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dataStr) != 4 {
+		t.Fatalf("messages: %v!=4", len(dataStr))
+	}
+
+	if dataStr[0].Level != "error" ||
+		dataStr[0].Message != "Resource start-up disabled since no STONITH resources have been defined" {
+		t.Fatalf("message 0: %+v", dataStr[0])
+	}
+
+	if dataStr[3].Level != "warning" || dataStr[3].Message != "Resource lustre-mgs has no operations" {
+		t.Fatalf("message 3: %+v", dataStr[3])
+	}
+}
+
+func TestParseCrmVerifyXML(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCrmVerifyXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCrmVerifyXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dataStr) != 5 {
+		t.Fatalf("messages: %v!=5", len(dataStr))
+	}
+
+	if dataStr[0].Level != "warning" {
+		t.Fatalf("message 0 level: %v!=warning", dataStr[0].Level)
+	}
+
+	if _, err := parseCrmVerifyXML([]byte("crm_verify: unrecognized option '--output-as'")); err == nil {
+		t.Fatalf("text output: err==nil")
+	}
+
+	failed := `<pacemaker-result api-version="2.25" request="crm_verify --output-as xml -L -V">
+  <status code="102" message="Not connected"/>
+</pacemaker-result>`
+	if _, err := parseCrmVerifyXML([]byte(failed)); err == nil {
+		t.Fatalf("status code 102: err==nil")
+	}
+}
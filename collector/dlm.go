@@ -0,0 +1,87 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type dlmCollector struct {
+	dlmLockspaceMembers     *prometheus.Desc
+	dlmLockspaceRecovery    *prometheus.Desc
+	dlmLockspaceFencingWait *prometheus.Desc
+	dlmLockspaceChangeSeq   *prometheus.Desc
+	dlmQuorate              *prometheus.Desc
+	dlmNodeMember           *prometheus.Desc
+	dlmNodeFencePending     *prometheus.Desc
+}
+
+func init() {
+	registerCollector("dlm", defaultDisabled, NewDlmCollector)
+}
+
+// NewDlmCollector returns a new Collector exposing the DLM lockspaces.
+func NewDlmCollector() (Collector, error) {
+	return &dlmCollector{
+		dlmLockspaceMembers: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "dlm_lockspace", "members"),
+			"Number of members of the DLM lockspace.",
+			[]string{"lockspace"}, nil,
+		),
+		dlmLockspaceRecovery: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "dlm_lockspace", "recovery"),
+			"Whether a membership change of the DLM lockspace is being recovered.",
+			[]string{"lockspace"}, nil,
+		),
+		dlmLockspaceFencingWait: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "dlm_lockspace", "fencing_wait"),
+			"Whether the DLM lockspace recovery waits for a failed member to be fenced.",
+			[]string{"lockspace"}, nil,
+		),
+		dlmLockspaceChangeSeq: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "dlm_lockspace", "change_seq"),
+			"Sequence number of the latest membership change of the DLM lockspace.",
+			[]string{"lockspace"}, nil,
+		),
+		dlmQuorate: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "dlm", "quorate"),
+			"Whether dlm_controld sees the cluster as quorate.",
+			nil, nil,
+		),
+		dlmNodeMember: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "dlm", "node_member"),
+			"Whether the node is a member of the dlm_controld cluster.",
+			[]string{"node_id"}, nil,
+		),
+		dlmNodeFencePending: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "dlm", "node_fence_pending"),
+			"Whether dlm_controld waits for the node to be fenced.",
+			[]string{"node_id"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*dlmCollector).getDlmInfo to get the DLM lockspaces metrics.
+func (c *dlmCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getDlmInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get dlm information: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,253 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// DlmLockspaceStruct struct stores a dlm_tool ls lockspace
+type DlmLockspaceStruct struct {
+	Name        string
+	Members     float64
+	ChangeSeq   float64
+	Recovery    bool
+	FencingWait bool
+}
+
+// DlmStatusStruct struct stores the dlm_tool status information
+type DlmStatusStruct struct {
+	Quorate      bool
+	Members      map[string]bool
+	FencePending []string
+}
+
+// execute dlm_tool utility.
+func dlmToolExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*dlmToolPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *dlmToolPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// parseDlmChangeSeq returns the sequence number of a dlm_tool ls change,
+// e.g. 4 for "member 2 joined 0 remove 1 failed 1 seq 4,4".
+func parseDlmChangeSeq(fields []string) float64 {
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == "seq" {
+			return parseFloat(strings.Split(fields[i+1], ",")[0])
+		}
+	}
+
+	return 0
+}
+
+// parseDlmLockspaces returns the lockspaces of the dlm_tool ls output. A
+// lockspace in recovery has its pending membership in the "new change",
+// "new status" and "new members" lines.
+func parseDlmLockspaces(data []byte) ([]DlmLockspaceStruct, error) {
+	var lockspaces []DlmLockspaceStruct
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		if fields[0] == "name" {
+			lockspaces = append(lockspaces, DlmLockspaceStruct{Name: fields[1]})
+			continue
+		}
+
+		if len(lockspaces) == 0 {
+			continue
+		}
+
+		lockspace := &lockspaces[len(lockspaces)-1]
+
+		key := fields[0]
+		if key == "new" {
+			key = "new " + fields[1]
+			fields = fields[1:]
+		}
+
+		switch key {
+		case "flags":
+			// The kernel lockspace is stopped while dlm_controld recovers it.
+			if stringInSlice("kern_stop", fields[1:]) {
+				lockspace.Recovery = true
+			}
+		case "change":
+			lockspace.ChangeSeq = parseDlmChangeSeq(fields[1:])
+		case "members":
+			lockspace.Members = float64(len(fields) - 1)
+		case "new change":
+			lockspace.Recovery = true
+			lockspace.ChangeSeq = parseDlmChangeSeq(fields[1:])
+		case "new status":
+			if stringInSlice("fencing", fields[1:]) {
+				lockspace.FencingWait = true
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return lockspaces, err
+	}
+
+	return lockspaces, nil
+}
+
+// parseDlmStatus returns the dlm_tool status information, e.g.
+// "cluster nodeid 1 quorate 1 ring seq 84 84", "node 2 M add 15 ..." for a
+// member and "fence 3 nodedown pid 4120 ..." for a node waiting for fencing.
+func parseDlmStatus(data []byte) (DlmStatusStruct, error) {
+	status := DlmStatusStruct{
+		Members: make(map[string]bool),
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+
+		switch fields[0] {
+		case "cluster":
+			for i := 1; i+1 < len(fields); i++ {
+				if fields[i] == "quorate" {
+					status.Quorate = fields[i+1] == "1"
+				}
+			}
+		case "node":
+			status.Members[fields[1]] = fields[2] == "M"
+		case "fence":
+			status.FencePending = append(status.FencePending, fields[1])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return status, err
+	}
+
+	return status, nil
+}
+
+// getDlmInfo returns dlm_tool information
+func (c *dlmCollector) getDlmInfo(ch chan<- prometheus.Metric) error {
+	outBytes, err := dlmToolExec("ls")
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	lockspaces, err := parseDlmLockspaces(outBytes)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeDlmLockspaces(ch, lockspaces)
+
+	outBytes, err = dlmToolExec("status")
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	status, err := parseDlmStatus(outBytes)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeDlmStatus(ch, status)
+
+	return nil
+}
+
+// expose dlm lockspaces metrics
+func (c *dlmCollector) exposeDlmLockspaces(ch chan<- prometheus.Metric, lockspaces []DlmLockspaceStruct) {
+	for _, lockspace := range lockspaces {
+		ch <- prometheus.MustNewConstMetric(c.dlmLockspaceMembers,
+			prometheus.GaugeValue, lockspace.Members, lockspace.Name)
+		ch <- prometheus.MustNewConstMetric(c.dlmLockspaceChangeSeq,
+			prometheus.GaugeValue, lockspace.ChangeSeq, lockspace.Name)
+
+		if lockspace.Recovery {
+			ch <- prometheus.MustNewConstMetric(c.dlmLockspaceRecovery,
+				prometheus.GaugeValue, 1.0, lockspace.Name)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.dlmLockspaceRecovery,
+				prometheus.GaugeValue, 0.0, lockspace.Name)
+		}
+
+		if lockspace.FencingWait {
+			ch <- prometheus.MustNewConstMetric(c.dlmLockspaceFencingWait,
+				prometheus.GaugeValue, 1.0, lockspace.Name)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.dlmLockspaceFencingWait,
+				prometheus.GaugeValue, 0.0, lockspace.Name)
+		}
+	}
+}
+
+// expose dlm status metrics
+func (c *dlmCollector) exposeDlmStatus(ch chan<- prometheus.Metric, status DlmStatusStruct) {
+	if status.Quorate {
+		ch <- prometheus.MustNewConstMetric(c.dlmQuorate,
+			prometheus.GaugeValue, 1.0)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.dlmQuorate,
+			prometheus.GaugeValue, 0.0)
+	}
+
+	for nodeID, member := range status.Members {
+		if member {
+			ch <- prometheus.MustNewConstMetric(c.dlmNodeMember,
+				prometheus.GaugeValue, 1.0, nodeID)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.dlmNodeMember,
+				prometheus.GaugeValue, 0.0, nodeID)
+		}
+
+		if stringInSlice(nodeID, status.FencePending) {
+			ch <- prometheus.MustNewConstMetric(c.dlmNodeFencePending,
+				prometheus.GaugeValue, 1.0, nodeID)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.dlmNodeFencePending,
+				prometheus.GaugeValue, 0.0, nodeID)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testDlmToolLs     = "fixtures/dlm_tool_ls.txt"
+	testDlmToolStatus = "fixtures/dlm_tool_status.txt"
+)
+
+func TestParseDlmLockspaces(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testDlmToolLs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockspaces, err := parseDlmLockspaces(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lockspaces) != 2 {
+		t.Fatalf("lockspaces: %v!=2", len(lockspaces))
+	}
+
+	lockspace := lockspaces[0]
+	if lockspace.Members != 3 || lockspace.ChangeSeq != 3 ||
+		lockspace.Recovery || lockspace.FencingWait {
+		t.Fatalf("lockspace '%s': %+v", lockspace.Name, lockspace)
+	}
+
+	lockspace = lockspaces[1]
+	if lockspace.Name != "gfs2vol" || !lockspace.Recovery || !lockspace.FencingWait {
+		t.Fatalf("lockspace '%s' recovery/fencing wait: %v/%v!=true/true",
+			lockspace.Name, lockspace.Recovery, lockspace.FencingWait)
+	}
+
+	if lockspace.ChangeSeq != 4 {
+		t.Fatalf("lockspace '%s' change seq: %v!=4", lockspace.Name, lockspace.ChangeSeq)
+	}
+}
+
+func TestParseDlmStatus(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testDlmToolStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := parseDlmStatus(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !status.Quorate {
+		t.Fatalf("quorate: %v!=true", status.Quorate)
+	}
+
+	if !status.Members["1"] || status.Members["3"] {
+		t.Fatalf("members: %v", status.Members)
+	}
+
+	if len(status.FencePending) != 1 || status.FencePending[0] != "3" {
+		t.Fatalf("fence pending: %v!=[3]", status.FencePending)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type drbdCollector struct {
+	cibQuerier
+
+	drbdRole           *prometheus.Desc
+	drbdDiskState      *prometheus.Desc
+	drbdDeviceSize     *prometheus.Desc
+	drbdRead           *prometheus.Desc
+	drbdWritten        *prometheus.Desc
+	drbdALWrites       *prometheus.Desc
+	drbdBMWrites       *prometheus.Desc
+	drbdConnection     *prometheus.Desc
+	drbdPeerRole       *prometheus.Desc
+	drbdPeerDiskState  *prometheus.Desc
+	drbdOutOfSync      *prometheus.Desc
+	drbdSent           *prometheus.Desc
+	drbdReceived       *prometheus.Desc
+	drbdResyncProgress *prometheus.Desc
+}
+
+func init() {
+	registerCollector("drbd", defaultDisabled, NewDrbdCollector)
+}
+
+// NewDrbdCollector returns a new Collector exposing DRBD replication information.
+func NewDrbdCollector() (Collector, error) {
+	// The id and clone_id labels are the Pacemaker primitive and clone
+	// managing the DRBD resource, as in the pacemaker_resource_* metrics.
+	deviceLabels := []string{"resource", "id", "clone_id", "volume"}
+	peerDeviceLabels := []string{"resource", "id", "clone_id", "peer", "volume"}
+
+	return &drbdCollector{
+		drbdRole: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "role"),
+			"A metric with a constant '1' value labeled by DRBD resource and its local role.",
+			[]string{"resource", "id", "clone_id", "role"}, nil,
+		),
+		drbdDiskState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "disk_state"),
+			"A metric with a constant '1' value labeled by DRBD volume and its local disk state.",
+			[]string{"resource", "id", "clone_id", "volume", "disk_state"}, nil,
+		),
+		drbdDeviceSize: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "device_size_bytes"),
+			"Size of the DRBD volume in bytes.",
+			deviceLabels, nil,
+		),
+		drbdRead: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "read_bytes_total"),
+			"Number of bytes read from the local disk of the DRBD volume.",
+			deviceLabels, nil,
+		),
+		drbdWritten: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "written_bytes_total"),
+			"Number of bytes written to the local disk of the DRBD volume.",
+			deviceLabels, nil,
+		),
+		drbdALWrites: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "activity_log_writes_total"),
+			"Number of activity log updates of the DRBD volume.",
+			deviceLabels, nil,
+		),
+		drbdBMWrites: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "bitmap_writes_total"),
+			"Number of bitmap updates of the DRBD volume.",
+			deviceLabels, nil,
+		),
+		drbdConnection: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "connection_state"),
+			"A metric with a constant '1' value labeled by DRBD peer and the connection state.",
+			[]string{"resource", "id", "clone_id", "peer", "connection_state"}, nil,
+		),
+		drbdPeerRole: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "peer_role"),
+			"A metric with a constant '1' value labeled by DRBD peer and its role.",
+			[]string{"resource", "id", "clone_id", "peer", "role"}, nil,
+		),
+		drbdPeerDiskState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "peer_disk_state"),
+			"A metric with a constant '1' value labeled by DRBD peer volume and its disk state.",
+			[]string{"resource", "id", "clone_id", "peer", "volume", "disk_state"}, nil,
+		),
+		drbdOutOfSync: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "out_of_sync_bytes"),
+			"Number of bytes out of sync with the DRBD peer volume.",
+			peerDeviceLabels, nil,
+		),
+		drbdSent: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "sent_bytes_total"),
+			"Number of bytes sent to the DRBD peer volume.",
+			peerDeviceLabels, nil,
+		),
+		drbdReceived: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "received_bytes_total"),
+			"Number of bytes received from the DRBD peer volume.",
+			peerDeviceLabels, nil,
+		),
+		drbdResyncProgress: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "drbd", "resync_progress_ratio"),
+			"Progress of the running resync with the DRBD peer volume, from 0 to 1.",
+			peerDeviceLabels, nil,
+		),
+	}, nil
+}
+
+// Update calls (*drbdCollector).getDrbdInfo to get the DRBD metrics.
+func (c *drbdCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getDrbdInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get drbd information: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,447 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+var (
+	// procDrbdDevice matches a /proc/drbd device line, e.g. " 0: cs:Connected ...".
+	procDrbdDevice = regexp.MustCompile(`^\s*(\d+): (.*)$`)
+	// procDrbdSynced matches the resync progress of a /proc/drbd device.
+	procDrbdSynced = regexp.MustCompile(`sync'ed:\s*([\d.]+)%`)
+)
+
+// DRBD sizes and counters are in KiB.
+const drbdKiB = 1024
+
+// DrbdResourceStruct struct stores a DRBD resource
+type DrbdResourceStruct struct {
+	Name        string
+	Role        string
+	Devices     []DrbdDeviceStruct
+	Connections []DrbdConnectionStruct
+}
+
+// DrbdDeviceStruct struct stores a DRBD resource volume
+type DrbdDeviceStruct struct {
+	Volume    string
+	Minor     string
+	DiskState string
+	Size      float64
+	Read      float64
+	Written   float64
+	ALWrites  float64
+	BMWrites  float64
+}
+
+// DrbdConnectionStruct struct stores a DRBD resource connection
+type DrbdConnectionStruct struct {
+	Peer            string
+	ConnectionState string
+	PeerRole        string
+	PeerDevices     []DrbdPeerDeviceStruct
+}
+
+// DrbdPeerDeviceStruct struct stores a DRBD peer volume
+type DrbdPeerDeviceStruct struct {
+	Volume        string
+	Replication   string
+	PeerDiskState string
+	OutOfSync     float64
+	Sent          float64
+	Received      float64
+	Syncing       bool
+	Done          float64
+}
+
+// execute drbdsetup utility.
+func drbdsetupExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*drbdsetupPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *drbdsetupPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// drbdFields returns the key:value fields of a DRBD status line.
+func drbdFields(fields []string) map[string]string {
+	values := make(map[string]string)
+
+	for _, field := range fields {
+		keyValue := strings.SplitN(field, ":", 2)
+		if len(keyValue) == 2 {
+			values[keyValue[0]] = keyValue[1]
+		}
+	}
+
+	return values
+}
+
+// parseDrbdEvents2 returns the DRBD resources of drbdsetup events2 --now
+// --statistics.
+func parseDrbdEvents2(data []byte) ([]DrbdResourceStruct, error) {
+	var resources []DrbdResourceStruct
+
+	resourceIndex := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[0] != "exists" {
+			continue
+		}
+
+		values := drbdFields(fields[2:])
+
+		index, ok := resourceIndex[values["name"]]
+		if !ok {
+			index = len(resources)
+			resourceIndex[values["name"]] = index
+			resources = append(resources, DrbdResourceStruct{Name: values["name"]})
+		}
+
+		resource := &resources[index]
+
+		switch fields[1] {
+		case "resource":
+			resource.Role = values["role"]
+		case "device":
+			resource.Devices = append(resource.Devices, DrbdDeviceStruct{
+				Volume:    values["volume"],
+				Minor:     values["minor"],
+				DiskState: values["disk"],
+				Size:      parseFloat(values["size"]) * drbdKiB,
+				Read:      parseFloat(values["read"]) * drbdKiB,
+				Written:   parseFloat(values["written"]) * drbdKiB,
+				ALWrites:  parseFloat(values["al-writes"]),
+				BMWrites:  parseFloat(values["bm-writes"]),
+			})
+		case "connection":
+			resource.Connections = append(resource.Connections, DrbdConnectionStruct{
+				Peer:            values["conn-name"],
+				ConnectionState: values["connection"],
+				PeerRole:        values["role"],
+			})
+		case "peer-device":
+			peerDevice := DrbdPeerDeviceStruct{
+				Volume:        values["volume"],
+				Replication:   values["replication"],
+				PeerDiskState: values["peer-disk"],
+				OutOfSync:     parseFloat(values["out-of-sync"]) * drbdKiB,
+				Sent:          parseFloat(values["sent"]) * drbdKiB,
+				Received:      parseFloat(values["received"]) * drbdKiB,
+			}
+
+			if done, ok := values["done"]; ok {
+				peerDevice.Syncing = true
+				peerDevice.Done = parseFloat(done) / 100
+			}
+
+			for i := range resource.Connections {
+				if resource.Connections[i].Peer == values["conn-name"] {
+					resource.Connections[i].PeerDevices = append(
+						resource.Connections[i].PeerDevices, peerDevice)
+				}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return resources, err
+	}
+
+	return resources, nil
+}
+
+// drbdMinorStruct stores the DRBD resource and volume of a minor.
+type drbdMinorStruct struct {
+	Name   string
+	Volume string
+}
+
+// parseProcDrbd returns the DRBD resources of the DRBD 8 /proc/drbd file.
+// It has no resource names, so they are looked up by minor, and devices
+// without a name are named after the minor, e.g. drbd0. The volumes of a
+// resource are merged into it.
+func parseProcDrbd(data []byte, minors map[string]drbdMinorStruct) ([]DrbdResourceStruct, error) {
+	var (
+		resources  []DrbdResourceStruct
+		device     *DrbdDeviceStruct
+		peerDevice *DrbdPeerDeviceStruct
+	)
+
+	resourceIndex := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if match := procDrbdDevice.FindStringSubmatch(line); match != nil {
+			device, peerDevice = nil, nil
+
+			values := drbdFields(strings.Fields(match[2]))
+			if values["cs"] == "Unconfigured" {
+				continue
+			}
+
+			minor, ok := minors[match[1]]
+			if !ok {
+				minor = drbdMinorStruct{Name: "drbd" + match[1], Volume: "0"}
+			}
+
+			roles := strings.SplitN(values["ro"], "/", 2)
+			disks := strings.SplitN(values["ds"], "/", 2)
+
+			for len(roles) < 2 {
+				roles = append(roles, "")
+			}
+
+			for len(disks) < 2 {
+				disks = append(disks, "")
+			}
+
+			index, ok := resourceIndex[minor.Name]
+			if !ok {
+				index = len(resources)
+				resourceIndex[minor.Name] = index
+				resources = append(resources, DrbdResourceStruct{
+					Name: minor.Name,
+					Role: roles[0],
+					Connections: []DrbdConnectionStruct{{
+						ConnectionState: values["cs"],
+						PeerRole:        roles[1],
+					}},
+				})
+			}
+
+			resource := &resources[index]
+			resource.Devices = append(resource.Devices,
+				DrbdDeviceStruct{Volume: minor.Volume, Minor: match[1], DiskState: disks[0]})
+
+			connection := &resource.Connections[0]
+			connection.PeerDevices = append(connection.PeerDevices,
+				DrbdPeerDeviceStruct{Volume: minor.Volume, PeerDiskState: disks[1]})
+
+			device = &resource.Devices[len(resource.Devices)-1]
+			peerDevice = &connection.PeerDevices[len(connection.PeerDevices)-1]
+
+			continue
+		}
+
+		if device == nil {
+			continue
+		}
+
+		if match := procDrbdSynced.FindStringSubmatch(line); match != nil {
+			peerDevice.Syncing = true
+			peerDevice.Done = parseFloat(match[1]) / 100
+			continue
+		}
+
+		values := drbdFields(strings.Fields(line))
+		if _, ok := values["ns"]; !ok {
+			continue
+		}
+
+		device.Read = parseFloat(values["dr"]) * drbdKiB
+		device.Written = parseFloat(values["dw"]) * drbdKiB
+		device.ALWrites = parseFloat(values["al"])
+		device.BMWrites = parseFloat(values["bm"])
+		peerDevice.Sent = parseFloat(values["ns"]) * drbdKiB
+		peerDevice.Received = parseFloat(values["nr"]) * drbdKiB
+		peerDevice.OutOfSync = parseFloat(values["oos"]) * drbdKiB
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return resources, err
+	}
+
+	return resources, nil
+}
+
+// drbdMinors returns the DRBD resource and volume by minor, from the udev
+// /dev/drbd/by-res/<resource> and /dev/drbd/by-res/<resource>/<volume>
+// symlinks.
+func drbdMinors() map[string]drbdMinorStruct {
+	minors := make(map[string]drbdMinorStruct)
+
+	links, _ := filepath.Glob("/dev/drbd/by-res/*")
+	volumeLinks, _ := filepath.Glob("/dev/drbd/by-res/*/*")
+
+	for _, link := range append(links, volumeLinks...) {
+		device, err := filepath.EvalSymlinks(link)
+		if err != nil || !strings.HasPrefix(filepath.Base(device), "drbd") {
+			continue
+		}
+
+		nameVolume := strings.SplitN(strings.TrimPrefix(link, "/dev/drbd/by-res/"), "/", 2)
+		for len(nameVolume) < 2 {
+			nameVolume = append(nameVolume, "0")
+		}
+
+		minors[strings.TrimPrefix(filepath.Base(device), "drbd")] = drbdMinorStruct{
+			Name:   nameVolume[0],
+			Volume: nameVolume[1],
+		}
+	}
+
+	return minors
+}
+
+// parseDrbdPacemaker returns the Pacemaker primitive and clone managing
+// each DRBD resource, from the drbd_resource instance attribute.
+func parseDrbdPacemaker(cibStruct CibStruct) map[string]cibPrimitiveCloneStruct {
+	drbdPacemaker := make(map[string]cibPrimitiveCloneStruct)
+
+	add := func(primitives []CibPrimitiveStruct, cloneID string) {
+		for _, primitive := range primitives {
+			if primitive.Type != "drbd" {
+				continue
+			}
+
+			if name, ok := nvSetValue(primitive.InstanceAttributes, "drbd_resource"); ok {
+				drbdPacemaker[name] = cibPrimitiveCloneStruct{primitive.ID, cloneID}
+			}
+		}
+	}
+
+	resources := cibStruct.Configuration.Resources
+	add(resources.Primitive, "")
+
+	for _, clone := range append(append([]CibCloneStruct{}, resources.Clone...), resources.Master...) {
+		add(clone.Primitive, clone.ID)
+	}
+
+	return drbdPacemaker
+}
+
+// getDrbdPacemaker returns the Pacemaker resources managing DRBD from the
+// live CIB, or nothing when the CIB can not be queried.
+func (c *drbdCollector) getDrbdPacemaker() map[string]cibPrimitiveCloneStruct {
+	cibStruct, err := c.getCib()
+	if err != nil {
+		return nil
+	}
+
+	return parseDrbdPacemaker(cibStruct)
+}
+
+// getDrbdResources returns the DRBD resources from drbdsetup, falling back
+// to /proc/drbd for DRBD 8.
+func getDrbdResources() ([]DrbdResourceStruct, error) {
+	outBytes, err := drbdsetupExec("events2", "--now", "--statistics")
+	if err == nil {
+		return parseDrbdEvents2(outBytes)
+	}
+
+	data, procErr := ioutil.ReadFile(*procDrbdPath)
+	if procErr != nil {
+		return nil, err
+	}
+
+	return parseProcDrbd(data, drbdMinors())
+}
+
+// getDrbdInfo returns DRBD information
+func (c *drbdCollector) getDrbdInfo(ch chan<- prometheus.Metric) error {
+	resources, err := getDrbdResources()
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeDrbd(ch, resources, c.getDrbdPacemaker())
+
+	return nil
+}
+
+// expose DRBD metrics
+func (c *drbdCollector) exposeDrbd(ch chan<- prometheus.Metric, resources []DrbdResourceStruct, drbdPacemaker map[string]cibPrimitiveCloneStruct) {
+	for _, resource := range resources {
+		pacemaker := drbdPacemaker[resource.Name]
+		labels := []string{resource.Name, pacemaker.ID, pacemaker.CloneID}
+
+		ch <- prometheus.MustNewConstMetric(c.drbdRole,
+			prometheus.GaugeValue, 1.0, append(labels, resource.Role)...)
+
+		for _, device := range resource.Devices {
+			deviceLabels := append(labels[:3:3], device.Volume)
+
+			ch <- prometheus.MustNewConstMetric(c.drbdDiskState,
+				prometheus.GaugeValue, 1.0, append(deviceLabels, device.DiskState)...)
+
+			// The size is not in /proc/drbd.
+			if device.Size > 0 {
+				ch <- prometheus.MustNewConstMetric(c.drbdDeviceSize,
+					prometheus.GaugeValue, device.Size, deviceLabels...)
+			}
+
+			ch <- prometheus.MustNewConstMetric(c.drbdRead,
+				prometheus.CounterValue, device.Read, deviceLabels...)
+			ch <- prometheus.MustNewConstMetric(c.drbdWritten,
+				prometheus.CounterValue, device.Written, deviceLabels...)
+			ch <- prometheus.MustNewConstMetric(c.drbdALWrites,
+				prometheus.CounterValue, device.ALWrites, deviceLabels...)
+			ch <- prometheus.MustNewConstMetric(c.drbdBMWrites,
+				prometheus.CounterValue, device.BMWrites, deviceLabels...)
+		}
+
+		for _, connection := range resource.Connections {
+			peerLabels := append(labels[:3:3], connection.Peer)
+
+			ch <- prometheus.MustNewConstMetric(c.drbdConnection,
+				prometheus.GaugeValue, 1.0, append(peerLabels, connection.ConnectionState)...)
+			ch <- prometheus.MustNewConstMetric(c.drbdPeerRole,
+				prometheus.GaugeValue, 1.0, append(peerLabels, connection.PeerRole)...)
+
+			for _, peerDevice := range connection.PeerDevices {
+				peerDeviceLabels := append(peerLabels[:4:4], peerDevice.Volume)
+
+				ch <- prometheus.MustNewConstMetric(c.drbdPeerDiskState,
+					prometheus.GaugeValue, 1.0, append(peerDeviceLabels, peerDevice.PeerDiskState)...)
+				ch <- prometheus.MustNewConstMetric(c.drbdOutOfSync,
+					prometheus.GaugeValue, peerDevice.OutOfSync, peerDeviceLabels...)
+				ch <- prometheus.MustNewConstMetric(c.drbdSent,
+					prometheus.CounterValue, peerDevice.Sent, peerDeviceLabels...)
+				ch <- prometheus.MustNewConstMetric(c.drbdReceived,
+					prometheus.CounterValue, peerDevice.Received, peerDeviceLabels...)
+
+				if peerDevice.Syncing {
+					ch <- prometheus.MustNewConstMetric(c.drbdResyncProgress,
+						prometheus.GaugeValue, peerDevice.Done, peerDeviceLabels...)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testDrbdsetupEvents2 = "fixtures/drbdsetup_events2.txt"
+	testProcDrbd         = "fixtures/proc_drbd"
+	testProcDrbdVolumes  = "fixtures/proc_drbd_volumes"
+)
+
+func TestParseDrbdEvents2(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testDrbdsetupEvents2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseDrbdEvents2(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dataStr) != 2 {
+		t.Fatalf("resources: %v!=2", len(dataStr))
+	}
+
+	r0 := dataStr[0]
+	if r0.Name != "r0" || r0.Role != "Primary" || r0.Devices[0].DiskState != "UpToDate" {
+		t.Fatalf("r0: %+v", r0)
+	}
+
+	if r0.Devices[0].Size != 1048508*1024 {
+		t.Fatalf("r0 size: %v!=%v", r0.Devices[0].Size, 1048508*1024)
+	}
+
+	peerDevice := dataStr[1].Connections[0].PeerDevices[0]
+	if dataStr[1].Connections[0].Peer != "lustre-mds2" || !peerDevice.Syncing ||
+		peerDevice.Done != 0.25 || peerDevice.OutOfSync != 1572864*1024 {
+		t.Fatalf("r1 peer device: %+v", peerDevice)
+	}
+}
+
+func TestParseProcDrbd(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testProcDrbd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseProcDrbd(dataByte, map[string]drbdMinorStruct{"0": {"r0", "0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dataStr) != 2 {
+		t.Fatalf("resources: %v!=2", len(dataStr))
+	}
+
+	if dataStr[0].Name != "r0" || dataStr[1].Name != "drbd1" {
+		t.Fatalf("names: %v, %v!=r0, drbd1", dataStr[0].Name, dataStr[1].Name)
+	}
+
+	connection := dataStr[1].Connections[0]
+	if connection.ConnectionState != "SyncSource" || connection.PeerRole != "Secondary" {
+		t.Fatalf("drbd1 connection: %+v", connection)
+	}
+
+	peerDevice := connection.PeerDevices[0]
+	if peerDevice.PeerDiskState != "Inconsistent" || peerDevice.Done != 0.302 ||
+		peerDevice.OutOfSync != 524288*1024 {
+		t.Fatalf("drbd1 peer device: %+v", peerDevice)
+	}
+
+	if dataStr[0].Devices[0].Read != 2138*1024 {
+		t.Fatalf("r0 read: %v!=%v", dataStr[0].Devices[0].Read, 2138*1024)
+	}
+}
+
+func TestParseProcDrbdVolumes(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testProcDrbdVolumes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseProcDrbd(dataByte, map[string]drbdMinorStruct{
+		"0": {"r0", "0"},
+		"1": {"r0", "1"},
+		"2": {"r1", "0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The volumes of r0 are merged into one resource.
+	if len(dataStr) != 2 || dataStr[0].Name != "r0" || dataStr[1].Name != "r1" {
+		t.Fatalf("resources: %+v", dataStr)
+	}
+
+	devices := dataStr[0].Devices
+	if len(devices) != 2 || devices[0].Volume != "0" || devices[1].Volume != "1" {
+		t.Fatalf("r0 devices: %+v", devices)
+	}
+
+	if devices[1].Minor != "1" || devices[1].Read != 1024*1024 {
+		t.Fatalf("r0 volume 1: %+v", devices[1])
+	}
+
+	peerDevices := dataStr[0].Connections[0].PeerDevices
+	if len(peerDevices) != 2 || peerDevices[1].Volume != "1" || peerDevices[1].Sent != 4096*1024 {
+		t.Fatalf("r0 peer devices: %+v", peerDevices)
+	}
+
+	if dataStr[1].Connections[0].PeerDevices[0].OutOfSync != 8192*1024 {
+		t.Fatalf("r1 out of sync: %v!=%v",
+			dataStr[1].Connections[0].PeerDevices[0].OutOfSync, 8192*1024)
+	}
+}
+
+func TestParseDrbdPacemaker(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCib)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseCibXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drbdPacemaker := parseDrbdPacemaker(dataStr)
+	if drbdPacemaker["r0"].ID != "drbd-r0" || drbdPacemaker["r0"].CloneID != "drbd-r0-clone" {
+		t.Fatalf("r0: %+v", drbdPacemaker["r0"])
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type fenceDevicesCollector struct {
+	cibQuerier
+
+	fenceDeviceRegistered *prometheus.Desc
+	fenceDeviceTarget     *prometheus.Desc
+	fenceDeviceListOk     *prometheus.Desc
+	fenceTopologyLevel    *prometheus.Desc
+	fenceNodeDevices      *prometheus.Desc
+	fenceNodeFenceable    *prometheus.Desc
+}
+
+func init() {
+	registerCollector("fence_devices", defaultDisabled, NewFenceDevicesCollector)
+}
+
+// NewFenceDevicesCollector returns a new Collector exposing the fence devices and topology.
+func NewFenceDevicesCollector() (Collector, error) {
+	return &fenceDevicesCollector{
+		fenceDeviceRegistered: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fence", "device_registered"),
+			"A metric with a constant '1' value labeled by the fence devices registered with the fencer.",
+			[]string{"device"}, nil,
+		),
+		fenceDeviceTarget: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fence", "device_target"),
+			"A metric with a constant '1' value labeled by fence device and the node it can fence.",
+			[]string{"device", "target"}, nil,
+		),
+		fenceDeviceListOk: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fence", "device_list_ok"),
+			"Whether the fence device listed its targets, the last listed targets are exported otherwise.",
+			[]string{"device"}, nil,
+		),
+		fenceTopologyLevel: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fence", "topology_level"),
+			"A metric with a constant '1' value labeled by fencing topology target, level index and devices.",
+			[]string{"target", "index", "devices"}, nil,
+		),
+		fenceNodeDevices: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fence", "node_devices"),
+			"Number of registered fence devices able to fence the node.",
+			[]string{"node"}, nil,
+		),
+		fenceNodeFenceable: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fence", "node_fenceable"),
+			"Whether at least one registered fence device is able to fence the node.",
+			[]string{"node"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*fenceDevicesCollector).getFenceDevicesInfo to get the
+// fence devices metrics.
+func (c *fenceDevicesCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getFenceDevicesInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get fence devices information: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// fenceDeviceTargetsStruct stores the last targets listed by each fence
+// device, used when listing them fails on a later scrape.
+type fenceDeviceTargetsStruct struct {
+	sync.Mutex
+	targets map[string][]string
+}
+
+// fenceDeviceTargets is shared by the collectors, which are created on
+// every scrape.
+var fenceDeviceTargets = newFenceDeviceTargets()
+
+// newFenceDeviceTargets returns empty fence device targets.
+func newFenceDeviceTargets() *fenceDeviceTargetsStruct {
+	return &fenceDeviceTargetsStruct{
+		targets: make(map[string][]string),
+	}
+}
+
+// update stores the targets listed by the registered devices, keeping the
+// last good targets of the devices failing to list them, and forgetting the
+// devices no longer registered. The caller must hold the lock.
+func (c *fenceDeviceTargetsStruct) update(listed map[string][]string, failed []string) map[string][]string {
+	deviceTargets := make(map[string][]string)
+
+	for device, targets := range listed {
+		deviceTargets[device] = targets
+	}
+
+	for _, device := range failed {
+		deviceTargets[device] = c.targets[device]
+	}
+
+	c.targets = deviceTargets
+
+	return deviceTargets
+}
+
+// stonithAdminList returns the items of the stonith_admin XML list output.
+func stonithAdminList(stonithAdminStruct StonithAdminStruct) []string {
+	var items []string
+
+	for _, list := range stonithAdminStruct.List {
+		items = append(items, list.Item...)
+	}
+
+	return items
+}
+
+// getStonithAdminList runs stonith_admin and returns its list output.
+func getStonithAdminList(args ...string) ([]string, error) {
+	outBytes, err := stonithAdminExec(append(args, "--output-as", "xml")...)
+	if err != nil {
+		return nil, err
+	}
+
+	stonithAdminStruct, err := parseStonithAdminXML(outBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return stonithAdminList(stonithAdminStruct), nil
+}
+
+// fenceNodeDevices returns the number of fence devices able to fence each
+// configured node.
+func fenceNodeDevices(nodes []string, deviceTargets map[string][]string) map[string]float64 {
+	nodeDevices := make(map[string]float64)
+
+	for _, node := range nodes {
+		nodeDevices[node] = 0
+	}
+
+	for _, targets := range deviceTargets {
+		for _, target := range targets {
+			if _, ok := nodeDevices[target]; ok {
+				nodeDevices[target]++
+			}
+		}
+	}
+
+	return nodeDevices
+}
+
+// getFenceDevicesInfo returns the fence devices and topology information
+func (c *fenceDevicesCollector) getFenceDevicesInfo(ch chan<- prometheus.Metric) error {
+	devices, err := getStonithAdminList("--list-registered")
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	listed := make(map[string][]string)
+
+	var failed []string
+
+	for _, device := range devices {
+		targets, err := getStonithAdminList("--list-targets", device)
+		if err != nil {
+			log.Warnf("fence device %s: %v", device, err)
+			failed = append(failed, device)
+
+			continue
+		}
+
+		listed[device] = targets
+	}
+
+	fenceDeviceTargets.Lock()
+	deviceTargets := fenceDeviceTargets.update(listed, failed)
+	fenceDeviceTargets.Unlock()
+
+	cibStruct, err := c.getCib()
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeFenceDevices(ch, deviceTargets, failed)
+	c.exposeFenceTopology(ch, cibStruct)
+	c.exposeFenceNodes(ch, cibStruct, deviceTargets)
+
+	return nil
+}
+
+// expose fence devices metrics
+func (c *fenceDevicesCollector) exposeFenceDevices(ch chan<- prometheus.Metric, deviceTargets map[string][]string,
+	failed []string) {
+	for device, targets := range deviceTargets {
+		ch <- prometheus.MustNewConstMetric(c.fenceDeviceRegistered,
+			prometheus.GaugeValue, 1.0, device)
+
+		if stringInSlice(device, failed) {
+			ch <- prometheus.MustNewConstMetric(c.fenceDeviceListOk,
+				prometheus.GaugeValue, 0.0, device)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.fenceDeviceListOk,
+				prometheus.GaugeValue, 1.0, device)
+		}
+
+		for _, target := range targets {
+			ch <- prometheus.MustNewConstMetric(c.fenceDeviceTarget,
+				prometheus.GaugeValue, 1.0, device, target)
+		}
+	}
+}
+
+// expose fencing topology metrics
+func (c *fenceDevicesCollector) exposeFenceTopology(ch chan<- prometheus.Metric, cibStruct CibStruct) {
+	for _, level := range cibStruct.Configuration.FencingTopology.FencingLevel {
+		target := level.Target
+		if target == "" {
+			target = level.TargetPattern
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.fenceTopologyLevel,
+			prometheus.GaugeValue, 1.0, target, level.Index, level.Devices)
+	}
+}
+
+// expose fence nodes metrics
+func (c *fenceDevicesCollector) exposeFenceNodes(ch chan<- prometheus.Metric, cibStruct CibStruct, deviceTargets map[string][]string) {
+	var nodes []string
+
+	for _, node := range cibStruct.Configuration.Nodes.Node {
+		nodes = append(nodes, node.Uname)
+	}
+
+	for node, count := range fenceNodeDevices(nodes, deviceTargets) {
+		ch <- prometheus.MustNewConstMetric(c.fenceNodeDevices,
+			prometheus.GaugeValue, count, node)
+
+		if count > 0 {
+			ch <- prometheus.MustNewConstMetric(c.fenceNodeFenceable,
+				prometheus.GaugeValue, 1.0, node)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.fenceNodeFenceable,
+				prometheus.GaugeValue, 0.0, node)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testStonithAdminRegistered = "fixtures/stonith_admin_registered.xml"
+	testStonithAdminTargets    = "fixtures/stonith_admin_targets.xml"
+)
+
+func TestStonithAdminList(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testStonithAdminRegistered)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseStonithAdminXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	devices := stonithAdminList(dataStr)
+	if len(devices) != 3 || devices[0] != "fence-mds1" {
+		t.Fatalf("devices: %v!=[fence-mds1 fence-pdu-a fence-pdu-b]", devices)
+	}
+}
+
+func TestFenceNodeDevices(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testStonithAdminTargets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseStonithAdminXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deviceTargets := map[string][]string{
+		"fence-mds1":  {"lustre-mds1"},
+		"fence-pdu-a": stonithAdminList(dataStr),
+	}
+
+	nodeDevices := fenceNodeDevices([]string{"lustre-mds1", "lustre-mds2"}, deviceTargets)
+
+	if nodeDevices["lustre-mds1"] != 2 {
+		t.Fatalf("lustre-mds1 devices: %v!=2", nodeDevices["lustre-mds1"])
+	}
+
+	if count, ok := nodeDevices["lustre-mds2"]; !ok || count != 0 {
+		t.Fatalf("lustre-mds2 devices: %v!=0", count)
+	}
+
+	if _, ok := nodeDevices["lustre-mds3"]; ok {
+		t.Fatalf("lustre-mds3 is not a configured node")
+	}
+}
+
+func TestFenceDeviceTargets(t *testing.T) {
+	c := newFenceDeviceTargets()
+
+	c.update(map[string][]string{
+		"fence-mds1":  {"lustre-mds1"},
+		"fence-pdu-a": {"lustre-mds1", "lustre-mds2"},
+	}, nil)
+
+	// A device failing to list its targets keeps the last listed ones.
+	deviceTargets := c.update(map[string][]string{
+		"fence-mds1": {"lustre-mds1"},
+	}, []string{"fence-pdu-a", "fence-mds3"})
+
+	if len(deviceTargets["fence-pdu-a"]) != 2 {
+		t.Fatalf("fence-pdu-a targets: %v!=2", len(deviceTargets["fence-pdu-a"]))
+	}
+
+	if targets, ok := deviceTargets["fence-mds3"]; !ok || len(targets) != 0 {
+		t.Fatalf("fence-mds3 targets: %v!=0", len(targets))
+	}
+
+	// Devices no longer registered are forgotten.
+	c.update(map[string][]string{}, nil)
+
+	if len(c.targets) != 0 {
+		t.Fatalf("targets: %v!=0", len(c.targets))
+	}
+}
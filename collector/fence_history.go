@@ -0,0 +1,66 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type fenceHistoryCollector struct {
+	fenceActions        *prometheus.Desc
+	fenceActionsPending *prometheus.Desc
+	fenceLastTimestamp  *prometheus.Desc
+	fenceHistoryTotals  *fenceHistoryTotalsStruct
+}
+
+func init() {
+	registerCollector("fence_history", defaultEnabled, NewFenceHistoryCollector)
+}
+
+// NewFenceHistoryCollector returns a new Collector exposing the fencing history.
+func NewFenceHistoryCollector() (Collector, error) {
+	return &fenceHistoryCollector{
+		fenceActions: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fence", "actions_total"),
+			"Number of completed fencing actions per target node and status.",
+			[]string{"target", "status"}, nil,
+		),
+		fenceActionsPending: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fence", "actions_pending"),
+			"Number of pending fencing actions per target node.",
+			[]string{"target"}, nil,
+		),
+		fenceLastTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "fence", "last_timestamp_seconds"),
+			"Completion time of the last fencing action per target node, labeled by action, delegate and status.",
+			[]string{"target", "action", "delegate", "status"}, nil,
+		),
+		fenceHistoryTotals: fenceHistoryTotals,
+	}, nil
+}
+
+// Update calls (*fenceHistoryCollector).getFenceHistoryInfo to get the
+// fencing history metrics.
+func (c *fenceHistoryCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getFenceHistoryInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get fence history information: %s", err)
+	}
+
+	return nil
+}
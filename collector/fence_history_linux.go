@@ -0,0 +1,234 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"encoding/xml"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// Fencing event statuses.
+const (
+	fenceStatusSuccess = "success"
+	fenceStatusFailed  = "failed"
+	fenceStatusPending = "pending"
+)
+
+// fenceTotalKey identifies a fencing actions counter.
+type fenceTotalKey struct {
+	Target string
+	Status string
+}
+
+// fenceHistoryTotalsStruct accumulates the fencing actions counters from
+// the events seen on previous scrapes, as the fencer keeps a limited
+// history, which can be cleaned up. The seen events are stored with their
+// completion time.
+type fenceHistoryTotalsStruct struct {
+	sync.Mutex
+	seen   map[string]time.Time
+	totals map[fenceTotalKey]float64
+}
+
+// fenceHistoryTotals is shared by the collectors, which are created on
+// every scrape.
+var fenceHistoryTotals = newFenceHistoryTotals()
+
+// newFenceHistoryTotals returns empty fencing actions counters.
+func newFenceHistoryTotals() *fenceHistoryTotalsStruct {
+	return &fenceHistoryTotalsStruct{
+		seen:   make(map[string]time.Time),
+		totals: make(map[fenceTotalKey]float64),
+	}
+}
+
+// execute stonith_admin utility.
+func stonithAdminExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*stonithAdminPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *stonithAdminPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// parseStonithAdminXML returns an XML structs.
+func parseStonithAdminXML(data []byte) (StonithAdminStruct, error) {
+	var stonithAdminOut StonithAdminStruct
+
+	err := xml.Unmarshal(data, &stonithAdminOut)
+	if err != nil {
+		log.Errorln(err)
+		return stonithAdminOut, err
+	}
+
+	return stonithAdminOut, nil
+}
+
+// parseFenceCompleted returns the completion time of a fencing event.
+// Pacemaker 2.0 uses the ctime format, later versions an ISO 8601 date.
+func parseFenceCompleted(value string) (time.Time, error) {
+	completed, err := time.Parse(time.ANSIC, strings.TrimSpace(value))
+	if err == nil {
+		return completed, nil
+	}
+
+	return parseCibDate(value)
+}
+
+// key returns a string identifying a fencing event in the history.
+func (event FenceEventStruct) key() string {
+	return strings.Join([]string{event.Target, event.Action, event.Client,
+		event.Origin, event.Delegate, event.Status, event.Completed}, "|")
+}
+
+// update adds the completed events not seen on previous scrapes to the
+// counters. Seen events are only forgotten once older than the oldest event
+// in the history, as a partial or empty history does not mean they can not
+// come back. The caller must hold the lock.
+func (c *fenceHistoryTotalsStruct) update(events []FenceEventStruct) {
+	var oldest time.Time
+
+	current := make(map[string]bool)
+
+	for _, event := range events {
+		if event.Status == fenceStatusPending {
+			continue
+		}
+
+		key := event.key()
+		current[key] = true
+
+		completed, err := parseFenceCompleted(event.Completed)
+		if err == nil && (oldest.IsZero() || completed.Before(oldest)) {
+			oldest = completed
+		}
+
+		if _, ok := c.seen[key]; !ok {
+			c.seen[key] = completed
+			c.totals[fenceTotalKey{event.Target, event.Status}]++
+		}
+
+		// Make sure both counters exist for every target.
+		for _, status := range []string{fenceStatusSuccess, fenceStatusFailed} {
+			c.totals[fenceTotalKey{event.Target, status}] += 0
+		}
+	}
+
+	for key, completed := range c.seen {
+		if current[key] {
+			continue
+		}
+
+		// Events without a valid completion time are kept while in the history.
+		if completed.IsZero() || (!oldest.IsZero() && completed.Before(oldest)) {
+			delete(c.seen, key)
+		}
+	}
+}
+
+// lastFenceEvents returns the last completed fencing event per target.
+func lastFenceEvents(events []FenceEventStruct) map[string]FenceEventStruct {
+	lastEvents := make(map[string]FenceEventStruct)
+	lastTimes := make(map[string]time.Time)
+
+	for _, event := range events {
+		if event.Status == fenceStatusPending {
+			continue
+		}
+
+		completed, err := parseFenceCompleted(event.Completed)
+		if err != nil {
+			log.Warnf("fencing of %s: %v", event.Target, err)
+			continue
+		}
+
+		if last, ok := lastTimes[event.Target]; ok && !completed.After(last) {
+			continue
+		}
+
+		lastEvents[event.Target] = event
+		lastTimes[event.Target] = completed
+	}
+
+	return lastEvents
+}
+
+// getFenceHistoryInfo returns stonith_admin fencing history information
+func (c *fenceHistoryCollector) getFenceHistoryInfo(ch chan<- prometheus.Metric) error {
+	outBytes, err := stonithAdminExec("--history", "*", "--output-as", "xml")
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	stonithAdminStruct, err := parseStonithAdminXML(outBytes)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	c.exposeFenceHistory(ch, stonithAdminStruct.FenceHistory.FenceEvent)
+
+	return nil
+}
+
+// expose fencing history metrics
+func (c *fenceHistoryCollector) exposeFenceHistory(ch chan<- prometheus.Metric, events []FenceEventStruct) {
+	c.fenceHistoryTotals.Lock()
+	defer c.fenceHistoryTotals.Unlock()
+
+	c.fenceHistoryTotals.update(events)
+
+	for key, total := range c.fenceHistoryTotals.totals {
+		ch <- prometheus.MustNewConstMetric(c.fenceActions,
+			prometheus.CounterValue, total, key.Target, key.Status)
+	}
+
+	pending := make(map[string]float64)
+	for key := range c.fenceHistoryTotals.totals {
+		pending[key.Target] = 0
+	}
+
+	for _, event := range events {
+		if event.Status == fenceStatusPending {
+			pending[event.Target]++
+		}
+	}
+
+	for target, count := range pending {
+		ch <- prometheus.MustNewConstMetric(c.fenceActionsPending,
+			prometheus.GaugeValue, count, target)
+	}
+
+	for target, event := range lastFenceEvents(events) {
+		completed, _ := parseFenceCompleted(event.Completed)
+
+		ch <- prometheus.MustNewConstMetric(c.fenceLastTimestamp,
+			prometheus.GaugeValue, float64(completed.Unix()), target,
+			event.Action, event.Delegate, event.Status)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testStonithAdminHistory = "fixtures/stonith_admin_history.xml"
+)
+
+func TestParseStonithAdminHistory(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testStonithAdminHistory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseStonithAdminXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := dataStr.FenceHistory.FenceEvent
+	if len(events) != 5 {
+		t.Fatalf("events: %v!=5", len(events))
+	}
+
+	lastEvents := lastFenceEvents(events)
+	if lastEvents["lustre-mds2"].Status != "success" {
+		t.Fatalf("lustre-mds2 last status: %v!=success", lastEvents["lustre-mds2"].Status)
+	}
+
+	completed, err := parseFenceCompleted(lastEvents["lustre-mds1"].Completed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if completed.Unix() != 1530122531 {
+		t.Fatalf("lustre-mds1 last completed: %v!=1530122531", completed.Unix())
+	}
+}
+
+func TestFenceHistoryTotals(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testStonithAdminHistory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseStonithAdminXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := newFenceHistoryTotals()
+	events := dataStr.FenceHistory.FenceEvent
+
+	// The same history scraped twice is only counted once.
+	c.update(events)
+	c.update(events)
+
+	if c.totals[fenceTotalKey{"lustre-mds2", "failed"}] != 1 {
+		t.Fatalf("lustre-mds2 failed: %v!=1", c.totals[fenceTotalKey{"lustre-mds2", "failed"}])
+	}
+
+	// Events coming back after an empty history are not counted again.
+	c.update(events[:1])
+	c.update(events)
+
+	if c.totals[fenceTotalKey{"lustre-mds2", "success"}] != 1 {
+		t.Fatalf("lustre-mds2 success: %v!=1", c.totals[fenceTotalKey{"lustre-mds2", "success"}])
+	}
+
+	// Only the events older than the oldest one in the history are forgotten.
+	c.update(events[:3])
+
+	if len(c.seen) != 2 {
+		t.Fatalf("seen: %v!=2", len(c.seen))
+	}
+
+	if c.totals[fenceTotalKey{"lustre-mds2", "failed"}] != 1 {
+		t.Fatalf("lustre-mds2 failed: %v!=1", c.totals[fenceTotalKey{"lustre-mds2", "failed"}])
+	}
+
+	if c.totals[fenceTotalKey{"lustre-mds1", "failed"}] != 0 {
+		t.Fatalf("lustre-mds1 failed: %v!=0", c.totals[fenceTotalKey{"lustre-mds1", "failed"}])
+	}
+}
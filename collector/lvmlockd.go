@@ -0,0 +1,67 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type lvmlockdCollector struct {
+	cibQuerier
+
+	lvmlockdVGLockType         *prometheus.Desc
+	lvmlockdVGLockspaceStarted *prometheus.Desc
+	lvmlockdVGLockstartError   *prometheus.Desc
+}
+
+func init() {
+	registerCollector("lvmlockd", defaultDisabled, NewLvmlockdCollector)
+}
+
+// NewLvmlockdCollector returns a new Collector exposing the lvmlockd shared
+// volume groups lockspaces.
+func NewLvmlockdCollector() (Collector, error) {
+	return &lvmlockdCollector{
+		lvmlockdVGLockType: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "lvmlockd", "vg_lock_type"),
+			"A metric with a constant '1' value labeled by the lock type and lock arguments of the shared volume group.",
+			[]string{"vg", "id", "clone_id", "lock_type", "lock_args"}, nil,
+		),
+		lvmlockdVGLockspaceStarted: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "lvmlockd", "vg_lockspace_started"),
+			"Whether the lockspace of the shared volume group is started.",
+			[]string{"vg", "id", "clone_id"}, nil,
+		),
+		lvmlockdVGLockstartError: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "lvmlockd", "vg_lockstart_error"),
+			"Whether starting the lockspace of the shared volume group failed.",
+			[]string{"vg", "id", "clone_id"}, nil,
+		),
+	}, nil
+}
+
+// Update calls (*lvmlockdCollector).getLvmlockdInfo to get the lvmlockd
+// metrics.
+func (c *lvmlockdCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getLvmlockdInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get lvmlockd information: %s", err)
+	}
+
+	return nil
+}
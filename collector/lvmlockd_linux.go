@@ -0,0 +1,291 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// LvmlockdVGStruct struct stores a shared volume group and its lockspace
+type LvmlockdVGStruct struct {
+	Name           string
+	LockType       string
+	LockArgs       string
+	Started        bool
+	LockstartError bool
+}
+
+// execute lvmlockctl utility.
+func lvmlockctlExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*lvmlockctlPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *lvmlockctlPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// execute vgs utility, or its wrapper.
+func vgsExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*vgsPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	// vgs fails when a shared volume group can not be locked, the other
+	// volume groups are still reported.
+	if _, ok := err.(*exec.ExitError); ok && len(out) > 0 {
+		return out, nil
+	}
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *vgsPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// parseLvmlockctlInfo returns the volume group lockspaces known to lvmlockd.
+// It reads both the formatted "lvmlockctl --info" output, where a started
+// lockspace is reported as "VG vg1 lock_type=sanlock <uuid>", and the raw
+// "info=ls ... create_fail=0 create_done=1" lines.
+func parseLvmlockctlInfo(data []byte) (map[string]LvmlockdVGStruct, error) {
+	vgs := make(map[string]LvmlockdVGStruct)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "VG":
+			vg := LvmlockdVGStruct{Name: fields[1], Started: true}
+			if len(fields) > 2 {
+				vg.LockType = strings.TrimPrefix(fields[2], "lock_type=")
+			}
+			vgs[vg.Name] = vg
+		case "info=ls":
+			values := make(map[string]string)
+			for _, field := range fields[1:] {
+				kv := strings.SplitN(field, "=", 2)
+				if len(kv) == 2 {
+					values[kv[0]] = kv[1]
+				}
+			}
+
+			// The global lockspace of dlm belongs to no volume group.
+			if values["vg_name"] == "" {
+				continue
+			}
+
+			vgs[values["vg_name"]] = LvmlockdVGStruct{
+				Name:           values["vg_name"],
+				LockType:       values["lm_type"],
+				LockArgs:       values["vg_args"],
+				Started:        values["create_done"] == "1" && values["create_fail"] != "1",
+				LockstartError: values["create_fail"] == "1",
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return vgs, err
+	}
+
+	return vgs, nil
+}
+
+// parseVgsLockType returns the shared volume groups of the
+// "vgs --noheadings --separator | -o+lock_type,lock_args" output, where the
+// lock type and arguments are the last two columns.
+func parseVgsLockType(data []byte) (map[string]LvmlockdVGStruct, error) {
+	vgs := make(map[string]LvmlockdVGStruct)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), "|")
+		if len(fields) < 3 {
+			continue
+		}
+
+		lockType := strings.TrimSpace(fields[len(fields)-2])
+		if lockType == "" || lockType == "none" {
+			continue
+		}
+
+		name := strings.TrimSpace(fields[0])
+		vgs[name] = LvmlockdVGStruct{
+			Name:     name,
+			LockType: lockType,
+			LockArgs: strings.TrimSpace(fields[len(fields)-1]),
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return vgs, err
+	}
+
+	return vgs, nil
+}
+
+// mergeLvmlockdVGs returns the shared volume groups reported by vgs or
+// lvmlockctl, sorted by name. A shared volume group without a lockspace in
+// lvmlockd is not started.
+func mergeLvmlockdVGs(vgs, lockspaces map[string]LvmlockdVGStruct) []LvmlockdVGStruct {
+	var result []LvmlockdVGStruct
+
+	for name, lockspace := range lockspaces {
+		if vg, ok := vgs[name]; ok {
+			if lockspace.LockType == "" {
+				lockspace.LockType = vg.LockType
+			}
+			if lockspace.LockArgs == "" {
+				lockspace.LockArgs = vg.LockArgs
+			}
+		}
+		result = append(result, lockspace)
+	}
+
+	for name, vg := range vgs {
+		if _, ok := lockspaces[name]; !ok {
+			result = append(result, vg)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result
+}
+
+// parseLvmlockdPacemaker returns the Pacemaker primitive and clone activating
+// each volume group, from the vgname instance attribute of LVM-activate or the
+// volgrpname one of the legacy LVM agent.
+func parseLvmlockdPacemaker(cibStruct CibStruct) map[string]cibPrimitiveCloneStruct {
+	lvmPacemaker := make(map[string]cibPrimitiveCloneStruct)
+
+	add := func(primitives []CibPrimitiveStruct, cloneID string) {
+		for _, primitive := range primitives {
+			if primitive.Type != "LVM-activate" && primitive.Type != "LVM" {
+				continue
+			}
+
+			if name, ok := nvSetValue(primitive.InstanceAttributes, "vgname", "volgrpname"); ok {
+				lvmPacemaker[name] = cibPrimitiveCloneStruct{primitive.ID, cloneID}
+			}
+		}
+	}
+
+	resources := cibStruct.Configuration.Resources
+	add(resources.Primitive, "")
+
+	for _, group := range resources.Group {
+		add(group.Primitive, "")
+	}
+
+	for _, clone := range append(append([]CibCloneStruct{}, resources.Clone...), resources.Master...) {
+		add(clone.Primitive, clone.ID)
+
+		for _, group := range clone.Group {
+			add(group.Primitive, clone.ID)
+		}
+	}
+
+	return lvmPacemaker
+}
+
+// getLvmlockdPacemaker returns the Pacemaker resources activating volume
+// groups from the live CIB, or nothing when the CIB can not be queried.
+func (c *lvmlockdCollector) getLvmlockdPacemaker() map[string]cibPrimitiveCloneStruct {
+	cibStruct, err := c.getCib()
+	if err != nil {
+		return nil
+	}
+
+	return parseLvmlockdPacemaker(cibStruct)
+}
+
+// getLvmlockdInfo returns lvmlockd information
+func (c *lvmlockdCollector) getLvmlockdInfo(ch chan<- prometheus.Metric) error {
+	outBytes, err := lvmlockctlExec("--info")
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	lockspaces, err := parseLvmlockctlInfo(outBytes)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	// Without vgs only the volume groups known to lvmlockd are exported.
+	vgs := make(map[string]LvmlockdVGStruct)
+	if outBytes, err = vgsExec("--noheadings", "--separator", "|",
+		"-o+lock_type,lock_args"); err == nil {
+		if vgs, err = parseVgsLockType(outBytes); err != nil {
+			log.Errorln(err)
+		}
+	}
+
+	c.exposeLvmlockd(ch, mergeLvmlockdVGs(vgs, lockspaces), c.getLvmlockdPacemaker())
+
+	return nil
+}
+
+// expose lvmlockd metrics
+func (c *lvmlockdCollector) exposeLvmlockd(ch chan<- prometheus.Metric, vgs []LvmlockdVGStruct, lvmPacemaker map[string]cibPrimitiveCloneStruct) {
+	for _, vg := range vgs {
+		pacemaker := lvmPacemaker[vg.Name]
+
+		ch <- prometheus.MustNewConstMetric(c.lvmlockdVGLockType,
+			prometheus.GaugeValue, 1.0, vg.Name, pacemaker.ID,
+			pacemaker.CloneID, vg.LockType, vg.LockArgs)
+
+		if vg.Started {
+			ch <- prometheus.MustNewConstMetric(c.lvmlockdVGLockspaceStarted,
+				prometheus.GaugeValue, 1.0, vg.Name, pacemaker.ID, pacemaker.CloneID)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.lvmlockdVGLockspaceStarted,
+				prometheus.GaugeValue, 0.0, vg.Name, pacemaker.ID, pacemaker.CloneID)
+		}
+
+		if vg.LockstartError {
+			ch <- prometheus.MustNewConstMetric(c.lvmlockdVGLockstartError,
+				prometheus.GaugeValue, 1.0, vg.Name, pacemaker.ID, pacemaker.CloneID)
+		} else {
+			ch <- prometheus.MustNewConstMetric(c.lvmlockdVGLockstartError,
+				prometheus.GaugeValue, 0.0, vg.Name, pacemaker.ID, pacemaker.CloneID)
+		}
+	}
+}
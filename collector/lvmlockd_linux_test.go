@@ -0,0 +1,114 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testLvmlockctlInfo    = "fixtures/lvmlockctl_info.txt"
+	testLvmlockctlInfoRaw = "fixtures/lvmlockctl_info_raw.txt"
+	testVgsLockType       = "fixtures/vgs_lock_type.txt"
+)
+
+func TestParseLvmlockctlInfo(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testLvmlockctlInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockspaces, err := parseLvmlockctlInfo(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lockspaces) != 1 || !lockspaces["vg1"].Started ||
+		lockspaces["vg1"].LockType != "sanlock" {
+		t.Fatalf("lockspaces: %+v!=vg1 sanlock started", lockspaces)
+	}
+
+	dataByte, err = ioutil.ReadFile(testLvmlockctlInfoRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockspaces, err = parseLvmlockctlInfo(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lockspaces) != 2 {
+		t.Fatalf("lockspaces: %v!=2", len(lockspaces))
+	}
+
+	if vg := lockspaces["vg2"]; vg.Started || !vg.LockstartError {
+		t.Fatalf("vg '%s' started/lockstart error: %v/%v!=false/true",
+			vg.Name, vg.Started, vg.LockstartError)
+	}
+}
+
+func TestParseVgsLockType(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testVgsLockType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vgs, err := parseVgsLockType(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(vgs) != 2 {
+		t.Fatalf("shared vgs: %v!=2", len(vgs))
+	}
+
+	if vgs["vg2"].LockType != "sanlock" || vgs["vg2"].LockArgs != "1.0.0:lvmlock" {
+		t.Fatalf("vg 'vg2' lock type/args: %v/%v!=sanlock/1.0.0:lvmlock",
+			vgs["vg2"].LockType, vgs["vg2"].LockArgs)
+	}
+
+	dataByte, err = ioutil.ReadFile(testLvmlockctlInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockspaces, err := parseLvmlockctlInfo(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged := mergeLvmlockdVGs(vgs, lockspaces)
+	if len(merged) != 2 || !merged[0].Started || merged[1].Started {
+		t.Fatalf("merged vgs: %+v!=vg1 started, vg2 not started", merged)
+	}
+}
+
+func TestParseLvmlockdPacemaker(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testCib)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cibStruct, err := parseCibXML(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lvmPacemaker := parseLvmlockdPacemaker(cibStruct)
+	if lvmPacemaker["vg1"].ID != "vg1" || lvmPacemaker["vg1"].CloneID != "shared-vg1-clone" {
+		t.Fatalf("vg 'vg1' resource: %+v!=vg1/shared-vg1-clone", lvmPacemaker["vg1"])
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	pacemakerLogPatterns = kingpin.Flag("collector.pacemaker_log.pattern",
+		"Event counted by the pacemaker_log collector, as NAME=REGEX, replacing the built-in event of the same name. Can be repeated.").Strings()
+
+	// pacemakerLogTotals is shared by the collectors, which are created on
+	// every scrape, and updated by the log tailer started with the first one.
+	pacemakerLogTotals *pacemakerLogTotalsStruct
+	pacemakerLogOnce   sync.Once
+	pacemakerLogErr    error
+)
+
+type pacemakerLogCollector struct {
+	pacemakerLogMessages *prometheus.Desc
+	pacemakerLogEvents   *prometheus.Desc
+	pacemakerLogTotals   *pacemakerLogTotalsStruct
+}
+
+func init() {
+	registerCollector("pacemaker_log", defaultDisabled, NewPacemakerLogCollector)
+}
+
+// NewPacemakerLogCollector returns a new Collector exposing the pacemaker log
+// counters, and starts following the log file on its first call.
+func NewPacemakerLogCollector() (Collector, error) {
+	pacemakerLogOnce.Do(func() {
+		var patterns []pacemakerLogPattern
+
+		patterns, pacemakerLogErr = parsePacemakerLogPatterns(*pacemakerLogPatterns)
+		if pacemakerLogErr != nil {
+			return
+		}
+
+		pacemakerLogTotals = newPacemakerLogTotals(patterns)
+		go followPacemakerLog(*pacemakerLogPath, pacemakerLogPollInterval,
+			pacemakerLogTotals.update)
+	})
+
+	if pacemakerLogErr != nil {
+		return nil, fmt.Errorf("couldn't parse pacemaker log patterns: %s", pacemakerLogErr)
+	}
+
+	return &pacemakerLogCollector{
+		pacemakerLogMessages: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "log", "messages_total"),
+			"Number of warning, error and critical pacemaker log messages per daemon.",
+			[]string{"daemon", "level"}, nil,
+		),
+		pacemakerLogEvents: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "log", "events_total"),
+			"Number of pacemaker log messages matching an event pattern.",
+			[]string{"event"}, nil,
+		),
+		pacemakerLogTotals: pacemakerLogTotals,
+	}, nil
+}
+
+// Update calls (*pacemakerLogCollector).getPacemakerLogInfo to get the
+// pacemaker log metrics.
+func (c *pacemakerLogCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getPacemakerLogInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get pacemaker log information: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,293 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// pacemakerLogPollInterval is the interval at which the log file is read.
+const pacemakerLogPollInterval = time.Second
+
+// pacemakerLogLines match the daemon and level of a pacemaker 2 log line,
+// e.g. "Jun 29 15:40:44.120 node1 pacemaker-controld  [1880] (func) info: ...",
+// and of a pacemaker 1.1 one, e.g. "Jun 29 15:40:46 [1234] node1 crmd: error: ...".
+var pacemakerLogLines = []*regexp.Regexp{
+	regexp.MustCompile(`^\w{3} +\d+ [\d:.]+ \S+ ([^\s\[]+)\s*\[\d+\] \([^)]*\)\s+(\w+):`),
+	regexp.MustCompile(`^\w{3} +\d+ [\d:.]+ \[\d+\] \S+\s+([^\s:]+):\s+(\w+):`),
+}
+
+// pacemakerLogLevels maps the counted log levels to their label values.
+var pacemakerLogLevels = map[string]string{
+	"warning": "warning",
+	"error":   "error",
+	"crit":    "critical",
+}
+
+// pacemakerLogPattern stores an event name and the regexp of its messages.
+type pacemakerLogPattern struct {
+	name   string
+	regexp *regexp.Regexp
+}
+
+// defaultPacemakerLogPatterns are the built-in events.
+var defaultPacemakerLogPatterns = []pacemakerLogPattern{
+	{"transition_abort", regexp.MustCompile(`Transition (\d+ )?aborted`)},
+	{"election", regexp.MustCompile(`State transition \S+ -> S_ELECTION\b`)},
+	{"fencing_scheduled", regexp.MustCompile(`(?i)Scheduling node \S+ for (STONITH|fencing)`)},
+	// The controller logs the result of every resource operation.
+	{"operation_timeout", regexp.MustCompile(`Result of \S+ operation for \S+ on \S+: Timed Out\b`)},
+}
+
+// pacemakerLogMessageKey identifies a log messages counter.
+type pacemakerLogMessageKey struct {
+	daemon string
+	level  string
+}
+
+// pacemakerLogTotalsStruct accumulates the counters of the log lines read
+// since the exporter started.
+type pacemakerLogTotalsStruct struct {
+	sync.Mutex
+	patterns []pacemakerLogPattern
+	messages map[pacemakerLogMessageKey]float64
+	events   map[string]float64
+}
+
+// pacemakerLogTailer follows a log file across its rotations, by renaming
+// or truncation.
+type pacemakerLogTailer struct {
+	path    string
+	file    *os.File
+	info    os.FileInfo
+	offset  int64
+	reader  *bufio.Reader
+	partial string
+}
+
+// parsePacemakerLogPatterns returns the built-in events with the NAME=REGEX
+// user ones, which replace the built-in event of the same name.
+func parsePacemakerLogPatterns(values []string) ([]pacemakerLogPattern, error) {
+	patterns := append([]pacemakerLogPattern{}, defaultPacemakerLogPatterns...)
+
+	for _, value := range values {
+		nameRegexp := strings.SplitN(value, "=", 2)
+		if len(nameRegexp) != 2 || nameRegexp[0] == "" {
+			return nil, fmt.Errorf("invalid pattern '%s', expected NAME=REGEX", value)
+		}
+
+		re, err := regexp.Compile(nameRegexp[1])
+		if err != nil {
+			return nil, err
+		}
+
+		pattern := pacemakerLogPattern{nameRegexp[0], re}
+		replaced := false
+
+		for i := range patterns {
+			if patterns[i].name == pattern.name {
+				patterns[i] = pattern
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns, nil
+}
+
+// parsePacemakerLogLine returns the daemon and level of a log line.
+func parsePacemakerLogLine(line string) (string, string, bool) {
+	for _, re := range pacemakerLogLines {
+		if match := re.FindStringSubmatch(line); match != nil {
+			return match[1], match[2], true
+		}
+	}
+
+	return "", "", false
+}
+
+// newPacemakerLogTotals returns empty log counters for the events.
+func newPacemakerLogTotals(patterns []pacemakerLogPattern) *pacemakerLogTotalsStruct {
+	totals := &pacemakerLogTotalsStruct{
+		patterns: patterns,
+		messages: make(map[pacemakerLogMessageKey]float64),
+		events:   make(map[string]float64),
+	}
+
+	// Make sure every event counter exists.
+	for _, pattern := range patterns {
+		totals.events[pattern.name] = 0
+	}
+
+	return totals
+}
+
+// update counts a log line.
+func (c *pacemakerLogTotalsStruct) update(line string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if daemon, level, ok := parsePacemakerLogLine(line); ok {
+		if label, ok := pacemakerLogLevels[level]; ok {
+			c.messages[pacemakerLogMessageKey{daemon, label}]++
+		}
+	}
+
+	for _, pattern := range c.patterns {
+		if pattern.regexp.MatchString(line) {
+			c.events[pattern.name]++
+		}
+	}
+}
+
+// open opens the log file, at its end when seekEnd is set.
+func (t *pacemakerLogTailer) open(seekEnd bool) error {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	var offset int64
+	if seekEnd {
+		offset, err = file.Seek(0, io.SeekEnd)
+		if err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	t.file = file
+	t.info = info
+	t.offset = offset
+	t.reader = bufio.NewReader(file)
+	t.partial = ""
+
+	return nil
+}
+
+// readLines calls handle for every complete line written since the last
+// call, an incomplete last line is kept for the next one.
+func (t *pacemakerLogTailer) readLines(handle func(string)) {
+	for {
+		line, err := t.reader.ReadString('\n')
+		t.offset += int64(len(line))
+
+		if err != nil {
+			t.partial += line
+			return
+		}
+
+		handle(strings.TrimRight(t.partial+line, "\r\n"))
+		t.partial = ""
+	}
+}
+
+// poll reads the lines written since the last poll. After a rotation the
+// rest of the old file is read before the new one, from its start.
+func (t *pacemakerLogTailer) poll(handle func(string)) {
+	if t.file == nil {
+		if err := t.open(false); err != nil {
+			return
+		}
+	}
+
+	t.readLines(handle)
+
+	info, err := os.Stat(t.path)
+	if err != nil {
+		// Rotated and not created again yet.
+		return
+	}
+
+	if !os.SameFile(info, t.info) {
+		t.file.Close()
+		t.file = nil
+
+		if err := t.open(false); err == nil {
+			t.readLines(handle)
+		}
+
+		return
+	}
+
+	if info.Size() < t.offset {
+		// Truncated in place, e.g. by logrotate copytruncate.
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			log.Errorln(err)
+			return
+		}
+
+		t.offset = 0
+		t.reader.Reset(t.file)
+		t.partial = ""
+		t.readLines(handle)
+	}
+}
+
+// followPacemakerLog calls handle for every line appended to the log file.
+func followPacemakerLog(path string, interval time.Duration, handle func(string)) {
+	tailer := &pacemakerLogTailer{path: path}
+
+	// Only the lines written after the exporter started are counted.
+	if err := tailer.open(true); err != nil {
+		log.Warnf("couldn't open pacemaker log: %s", err)
+	}
+
+	for range time.Tick(interval) {
+		tailer.poll(handle)
+	}
+}
+
+// getPacemakerLogInfo returns the pacemaker log counters
+func (c *pacemakerLogCollector) getPacemakerLogInfo(ch chan<- prometheus.Metric) error {
+	c.pacemakerLogTotals.Lock()
+	defer c.pacemakerLogTotals.Unlock()
+
+	c.exposePacemakerLog(ch, c.pacemakerLogTotals)
+
+	return nil
+}
+
+// expose pacemaker log metrics
+func (c *pacemakerLogCollector) exposePacemakerLog(ch chan<- prometheus.Metric, totals *pacemakerLogTotalsStruct) {
+	for key, value := range totals.messages {
+		ch <- prometheus.MustNewConstMetric(c.pacemakerLogMessages,
+			prometheus.CounterValue, value, key.daemon, key.level)
+	}
+
+	for event, value := range totals.events {
+		ch <- prometheus.MustNewConstMetric(c.pacemakerLogEvents,
+			prometheus.CounterValue, value, event)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	testPacemakerLog = "fixtures/pacemaker.log"
+)
+
+func TestPacemakerLogTotals(t *testing.T) {
+	patterns, err := parsePacemakerLogPatterns([]string{
+		`election=Election Timeout`,
+		`lustre_mgs=lustre-mgs`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(testPacemakerLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	totals := newPacemakerLogTotals(patterns)
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		totals.update(scanner.Text())
+	}
+
+	messages := map[pacemakerLogMessageKey]float64{
+		{"pacemaker-controld", "error"}:     1,
+		{"pacemaker-schedulerd", "warning"}: 3,
+		{"pacemaker-fenced", "critical"}:    1,
+		{"crmd", "error"}:                   1,
+		{"crmd", "warning"}:                 1,
+	}
+
+	if len(totals.messages) != len(messages) {
+		t.Fatalf("messages: %v!=%v", totals.messages, messages)
+	}
+
+	for key, value := range messages {
+		if totals.messages[key] != value {
+			t.Fatalf("messages %v: %v!=%v", key, totals.messages[key], value)
+		}
+	}
+
+	events := map[string]float64{
+		"transition_abort":  1,
+		"election":          1,
+		"fencing_scheduled": 1,
+		"operation_timeout": 1,
+		"lustre_mgs":        3,
+	}
+
+	for event, value := range events {
+		if totals.events[event] != value {
+			t.Fatalf("event '%s': %v!=%v", event, totals.events[event], value)
+		}
+	}
+}
+
+func TestParsePacemakerLogPatternsInvalid(t *testing.T) {
+	for _, value := range []string{"no-regex", "=regex", "bad=("} {
+		if _, err := parsePacemakerLogPatterns([]string{value}); err == nil {
+			t.Fatalf("pattern '%s': no error", value)
+		}
+	}
+}
+
+func TestPacemakerLogTailer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pacemaker_log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "pacemaker.log")
+	if err := ioutil.WriteFile(path, []byte("before start\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	handle := func(line string) { lines = append(lines, line) }
+
+	tailer := &pacemakerLogTailer{path: path}
+	if err := tailer.open(true); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("one\ntw")
+	tailer.poll(handle)
+	file.WriteString("o\n")
+	file.Close()
+
+	// Rotated by renaming, the rest of the old file is read first.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte("three\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tailer.poll(handle)
+
+	// Rotated by truncation.
+	if err := ioutil.WriteFile(path, []byte("4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tailer.poll(handle)
+
+	if strings.Join(lines, ",") != "one,two,three,4" {
+		t.Fatalf("lines: %v!=[one two three 4]", lines)
+	}
+}
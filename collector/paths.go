@@ -21,4 +21,58 @@ import (
 var (
 	// The path of the crm_mon binary.
 	crmMonPath = kingpin.Flag("path.crm_mon", "Pacemaker `crm_mon` path.").Default("/usr/sbin/crm_mon").String()
+	// The path of the cibadmin binary.
+	cibadminPath = kingpin.Flag("path.cibadmin", "Pacemaker `cibadmin` path.").Default("/usr/sbin/cibadmin").String()
+	// The path of the crm_verify binary.
+	crmVerifyPath = kingpin.Flag("path.crm_verify",
+		"Pacemaker `crm_verify` path.").Default("/usr/sbin/crm_verify").String()
+	// The path of the crm_simulate binary.
+	crmSimulatePath = kingpin.Flag("path.crm_simulate",
+		"Pacemaker `crm_simulate` path.").Default("/usr/sbin/crm_simulate").String()
+	// The path of the pacemaker log file.
+	pacemakerLogPath = kingpin.Flag("path.pacemaker-log",
+		"Pacemaker log file path.").Default("/var/log/pacemaker/pacemaker.log").String()
+	// The path of the socket receiving the alerts of the alert agent.
+	alertSocketPath = kingpin.Flag("path.alert-socket",
+		"Unix socket on which the alert collector receives the events of the alert-agent command.").Default("/run/pacemaker_exporter/alert.sock").String()
+	// The path of the stonith_admin binary.
+	stonithAdminPath = kingpin.Flag("path.stonith_admin",
+		"Pacemaker `stonith_admin` path.").Default("/usr/sbin/stonith_admin").String()
+	// The path of the booth binary.
+	boothPath = kingpin.Flag("path.booth", "Booth `booth` path.").Default("/usr/sbin/booth").String()
+	// The path of the sbd binary.
+	sbdPath = kingpin.Flag("path.sbd", "SBD `sbd` path.").Default("/usr/sbin/sbd").String()
+	// The path of the sbd configuration file.
+	sbdConfigPath = kingpin.Flag("path.sbd-config",
+		"SBD configuration file path.").Default("/etc/sysconfig/sbd").String()
+	// The path of the drbdsetup binary.
+	drbdsetupPath = kingpin.Flag("path.drbdsetup", "DRBD `drbdsetup` path.").Default("/usr/sbin/drbdsetup").String()
+	// The path of the DRBD 8 proc file.
+	procDrbdPath = kingpin.Flag("path.proc-drbd", "DRBD 8 proc file path.").Default("/proc/drbd").String()
+	// The path of the dlm_tool binary.
+	dlmToolPath = kingpin.Flag("path.dlm_tool", "DLM `dlm_tool` path.").Default("/usr/sbin/dlm_tool").String()
+	// The path of the lvmlockctl binary.
+	lvmlockctlPath = kingpin.Flag("path.lvmlockctl",
+		"LVM `lvmlockctl` path.").Default("/usr/sbin/lvmlockctl").String()
+	// The path of the vgs binary, or of a wrapper taking the same arguments.
+	vgsPath = kingpin.Flag("path.vgs",
+		"LVM `vgs` path, or the path of a wrapper taking the same arguments.").Default("/usr/sbin/vgs").String()
+	// The path of the corosync-quorumtool binary.
+	corosyncQuorumtoolPath = kingpin.Flag("path.corosync-quorumtool",
+		"Corosync `corosync-quorumtool` path.").Default("/usr/sbin/corosync-quorumtool").String()
+	// The path of the corosync-cfgtool binary.
+	corosyncCfgtoolPath = kingpin.Flag("path.corosync-cfgtool",
+		"Corosync `corosync-cfgtool` path.").Default("/usr/sbin/corosync-cfgtool").String()
+	// The path of the corosync-cmapctl binary.
+	corosyncCmapctlPath = kingpin.Flag("path.corosync-cmapctl",
+		"Corosync `corosync-cmapctl` path.").Default("/usr/sbin/corosync-cmapctl").String()
+	// The path of the corosync-qdevice-tool binary.
+	corosyncQdeviceToolPath = kingpin.Flag("path.corosync-qdevice-tool",
+		"Corosync `corosync-qdevice-tool` path.").Default("/usr/sbin/corosync-qdevice-tool").String()
+	// The path of the corosync-qnetd-tool binary.
+	corosyncQnetdToolPath = kingpin.Flag("path.corosync-qnetd-tool",
+		"Corosync `corosync-qnetd-tool` path.").Default("/usr/bin/corosync-qnetd-tool").String()
+	// The path of the corosync configuration file.
+	corosyncConfPath = kingpin.Flag("path.corosync-conf",
+		"Corosync configuration file path.").Default("/etc/corosync/corosync.conf").String()
 )
@@ -0,0 +1,101 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type sbdCollector struct {
+	cibQuerier
+
+	sbdDevices                *prometheus.Desc
+	sbdDeviceAccessible       *prometheus.Desc
+	sbdDeviceWatchdogTimeout  *prometheus.Desc
+	sbdDeviceMsgwaitTimeout   *prometheus.Desc
+	sbdDeviceMsgwaitValid     *prometheus.Desc
+	sbdNodeMessage            *prometheus.Desc
+	sbdWatchdogTimeout        *prometheus.Desc
+	sbdStonithWatchdogTimeout *prometheus.Desc
+	sbdStonithWatchdogValid   *prometheus.Desc
+}
+
+func init() {
+	registerCollector("sbd", defaultDisabled, NewSbdCollector)
+}
+
+// NewSbdCollector returns a new Collector exposing SBD information.
+func NewSbdCollector() (Collector, error) {
+	return &sbdCollector{
+		sbdDevices: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "sbd", "devices"),
+			"Number of SBD devices configured.",
+			nil, nil,
+		),
+		sbdDeviceAccessible: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "sbd", "device_accessible"),
+			"Whether the SBD device header can be read.",
+			[]string{"device"}, nil,
+		),
+		sbdDeviceWatchdogTimeout: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "sbd", "device_watchdog_timeout_seconds"),
+			"Watchdog timeout written in the SBD device header in seconds.",
+			[]string{"device"}, nil,
+		),
+		sbdDeviceMsgwaitTimeout: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "sbd", "device_msgwait_timeout_seconds"),
+			"Msgwait timeout written in the SBD device header in seconds.",
+			[]string{"device"}, nil,
+		),
+		sbdDeviceMsgwaitValid: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "sbd", "device_msgwait_timeout_valid"),
+			"Whether the msgwait timeout is at least twice the watchdog timeout of the SBD device.",
+			[]string{"device"}, nil,
+		),
+		sbdNodeMessage: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "sbd", "node_message"),
+			"Whether the message is in the slot of the node on the SBD device.",
+			[]string{"device", "slot", "node", "message"}, nil,
+		),
+		sbdWatchdogTimeout: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "sbd", "watchdog_timeout_seconds"),
+			"SBD_WATCHDOG_TIMEOUT of the SBD configuration in seconds.",
+			nil, nil,
+		),
+		sbdStonithWatchdogTimeout: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "sbd", "stonith_watchdog_timeout_seconds"),
+			"stonith-watchdog-timeout cluster property in seconds, negative to derive it from SBD_WATCHDOG_TIMEOUT.",
+			nil, nil,
+		),
+		sbdStonithWatchdogValid: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "sbd", "stonith_watchdog_timeout_valid"),
+			"Whether the stonith-watchdog-timeout cluster property exceeds SBD_WATCHDOG_TIMEOUT.",
+			nil, nil,
+		),
+	}, nil
+}
+
+// Update calls (*sbdCollector).getSbdInfo to get the SBD metrics.
+func (c *sbdCollector) Update(ch chan<- prometheus.Metric) error {
+	err := c.getSbdInfo(ch)
+	if err != nil {
+		return fmt.Errorf("couldn't get sbd information: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,312 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/log"
+)
+
+// sbdMessages are the messages a node slot on an SBD device can hold.
+var sbdMessages = []string{"clear", "test", "reset", "off", "exit", "crashdump"}
+
+// sbdDefaultWatchdogTimeout is the SBD_WATCHDOG_TIMEOUT default in seconds.
+const sbdDefaultWatchdogTimeout = 5
+
+// SbdConfigStruct struct stores the SBD configuration
+type SbdConfigStruct struct {
+	Devices         []string
+	WatchdogTimeout float64
+}
+
+// SbdHeaderStruct struct stores the sbd dump header information
+type SbdHeaderStruct struct {
+	WatchdogTimeout float64
+	MsgwaitTimeout  float64
+}
+
+// SbdSlotStruct struct stores an sbd list slot
+type SbdSlotStruct struct {
+	Slot    string
+	Node    string
+	Message string
+	Sender  string
+}
+
+// execute sbd utility.
+func sbdExec(args ...string) ([]byte, error) {
+	cmd := exec.Command(*sbdPath, args...)
+	// Disable localization for parsing.
+	cmd.Env = append(os.Environ(), "LANG=C")
+	out, err := cmd.Output()
+
+	if err != nil {
+		log.Errorf("error while calling '%s %s': %v", *sbdPath,
+			strings.Join(args, " "), err)
+	}
+
+	return out, err
+}
+
+// parseSbdConfig returns the SBD configuration of a shell variables file.
+func parseSbdConfig(data []byte) (SbdConfigStruct, error) {
+	config := SbdConfigStruct{WatchdogTimeout: sbdDefaultWatchdogTimeout}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		keyValue := strings.SplitN(line, "=", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		value := strings.Trim(strings.TrimSpace(keyValue[1]), `"'`)
+
+		switch strings.TrimSpace(keyValue[0]) {
+		case "SBD_DEVICE":
+			for _, device := range strings.Split(value, ";") {
+				if device = strings.TrimSpace(device); device != "" {
+					config.Devices = append(config.Devices, device)
+				}
+			}
+		case "SBD_WATCHDOG_TIMEOUT":
+			if timeout, err := strconv.ParseFloat(value, 64); err == nil {
+				config.WatchdogTimeout = timeout
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return config, err
+	}
+
+	return config, nil
+}
+
+// parseSbdDump returns the timeouts of an sbd dump header.
+func parseSbdDump(data []byte) (SbdHeaderStruct, error) {
+	var header SbdHeaderStruct
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		keyValue := strings.SplitN(scanner.Text(), ":", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		switch strings.TrimSpace(keyValue[0]) {
+		case "Timeout (watchdog)":
+			header.WatchdogTimeout = parseFloat(keyValue[1])
+		case "Timeout (msgwait)":
+			header.MsgwaitTimeout = parseFloat(keyValue[1])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return header, err
+	}
+
+	return header, nil
+}
+
+// parseSbdList returns the node slots of an sbd list.
+func parseSbdList(data []byte) ([]SbdSlotStruct, error) {
+	var slots []SbdSlotStruct
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+
+		slot := SbdSlotStruct{
+			Slot:    fields[0],
+			Node:    fields[1],
+			Message: fields[2],
+		}
+
+		if len(fields) > 3 {
+			slot.Sender = fields[3]
+		}
+
+		slots = append(slots, slot)
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Errorln(err)
+		return slots, err
+	}
+
+	return slots, nil
+}
+
+// stonithWatchdogTimeoutValid checks the stonith-watchdog-timeout cluster
+// property against SBD_WATCHDOG_TIMEOUT. A negative value makes Pacemaker
+// use twice SBD_WATCHDOG_TIMEOUT.
+func stonithWatchdogTimeoutValid(stonithWatchdogTimeout, watchdogTimeout float64) bool {
+	if stonithWatchdogTimeout < 0 {
+		return true
+	}
+
+	return stonithWatchdogTimeout > watchdogTimeout
+}
+
+// getStonithWatchdogTimeout returns the stonith-watchdog-timeout cluster
+// property from the CIB, if it is set to a non zero value.
+func (c *sbdCollector) getStonithWatchdogTimeout() (float64, bool) {
+	cibStruct, err := c.getCib()
+	if err != nil {
+		return 0, false
+	}
+
+	value, ok := nvSetValue(cibStruct.Configuration.CrmConfig.ClusterPropertySet,
+		"stonith-watchdog-timeout")
+	if !ok {
+		return 0, false
+	}
+
+	negative := strings.HasPrefix(strings.TrimSpace(value), "-")
+
+	timeout, err := parsePacemakerInterval(strings.TrimPrefix(strings.TrimSpace(value), "-"))
+	if err != nil {
+		log.Warnf("stonith-watchdog-timeout: %v", err)
+		return 0, false
+	}
+
+	if negative {
+		timeout = -timeout
+	}
+
+	return timeout, timeout != 0
+}
+
+// getSbdInfo returns SBD information
+func (c *sbdCollector) getSbdInfo(ch chan<- prometheus.Metric) error {
+	data, err := ioutil.ReadFile(*sbdConfigPath)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	config, err := parseSbdConfig(data)
+	if err != nil {
+		log.Errorln(err)
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.sbdDevices,
+		prometheus.GaugeValue, float64(len(config.Devices)))
+	ch <- prometheus.MustNewConstMetric(c.sbdWatchdogTimeout,
+		prometheus.GaugeValue, config.WatchdogTimeout)
+
+	for _, device := range config.Devices {
+		c.exposeSbdDevice(ch, device)
+	}
+
+	if timeout, ok := c.getStonithWatchdogTimeout(); ok {
+		c.exposeStonithWatchdogTimeout(ch, timeout, config.WatchdogTimeout)
+	}
+
+	return nil
+}
+
+// expose SBD device metrics. An unreadable device is reported, not an error.
+func (c *sbdCollector) exposeSbdDevice(ch chan<- prometheus.Metric, device string) {
+	dumpBytes, err := sbdExec("-d", device, "dump")
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.sbdDeviceAccessible,
+			prometheus.GaugeValue, 0.0, device)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.sbdDeviceAccessible,
+		prometheus.GaugeValue, 1.0, device)
+
+	header, err := parseSbdDump(dumpBytes)
+	if err == nil {
+		c.exposeSbdHeader(ch, device, header)
+	}
+
+	listBytes, err := sbdExec("-d", device, "list")
+	if err != nil {
+		return
+	}
+
+	slots, err := parseSbdList(listBytes)
+	if err == nil {
+		c.exposeSbdSlots(ch, device, slots)
+	}
+}
+
+// expose SBD device header metrics
+func (c *sbdCollector) exposeSbdHeader(ch chan<- prometheus.Metric, device string, header SbdHeaderStruct) {
+	ch <- prometheus.MustNewConstMetric(c.sbdDeviceWatchdogTimeout,
+		prometheus.GaugeValue, header.WatchdogTimeout, device)
+	ch <- prometheus.MustNewConstMetric(c.sbdDeviceMsgwaitTimeout,
+		prometheus.GaugeValue, header.MsgwaitTimeout, device)
+
+	if header.MsgwaitTimeout >= 2*header.WatchdogTimeout {
+		ch <- prometheus.MustNewConstMetric(c.sbdDeviceMsgwaitValid,
+			prometheus.GaugeValue, 1.0, device)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.sbdDeviceMsgwaitValid,
+			prometheus.GaugeValue, 0.0, device)
+	}
+}
+
+// expose SBD device slots metrics
+func (c *sbdCollector) exposeSbdSlots(ch chan<- prometheus.Metric, device string, slots []SbdSlotStruct) {
+	for _, slot := range slots {
+		for _, message := range sbdMessages {
+			if slot.Message == message {
+				ch <- prometheus.MustNewConstMetric(c.sbdNodeMessage,
+					prometheus.GaugeValue, 1.0, device, slot.Slot, slot.Node, message)
+			} else {
+				ch <- prometheus.MustNewConstMetric(c.sbdNodeMessage,
+					prometheus.GaugeValue, 0.0, device, slot.Slot, slot.Node, message)
+			}
+		}
+	}
+}
+
+// expose stonith-watchdog-timeout metrics
+func (c *sbdCollector) exposeStonithWatchdogTimeout(ch chan<- prometheus.Metric, stonithWatchdogTimeout, watchdogTimeout float64) {
+	ch <- prometheus.MustNewConstMetric(c.sbdStonithWatchdogTimeout,
+		prometheus.GaugeValue, stonithWatchdogTimeout)
+
+	if stonithWatchdogTimeoutValid(stonithWatchdogTimeout, watchdogTimeout) {
+		ch <- prometheus.MustNewConstMetric(c.sbdStonithWatchdogValid,
+			prometheus.GaugeValue, 1.0)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.sbdStonithWatchdogValid,
+			prometheus.GaugeValue, 0.0)
+	}
+}
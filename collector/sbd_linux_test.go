@@ -0,0 +1,101 @@
+// Copyright 2018 Mario Trangoni
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+const (
+	testSbdConfig = "fixtures/sbd_sysconfig"
+	testSbdDump   = "fixtures/sbd_dump.txt"
+	testSbdList   = "fixtures/sbd_list.txt"
+)
+
+func TestParseSbdConfig(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testSbdConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseSbdConfig(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dataStr.Devices) != 2 || dataStr.Devices[1] != "/dev/disk/by-id/scsi-sbd-b" {
+		t.Fatalf("Devices: %v", dataStr.Devices)
+	}
+
+	if dataStr.WatchdogTimeout != 5 {
+		t.Fatalf("WatchdogTimeout: %v!=5", dataStr.WatchdogTimeout)
+	}
+}
+
+func TestParseSbdDump(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testSbdDump)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseSbdDump(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dataStr.WatchdogTimeout != 15 || dataStr.MsgwaitTimeout != 30 {
+		t.Fatalf("watchdog/msgwait: %v/%v!=15/30", dataStr.WatchdogTimeout,
+			dataStr.MsgwaitTimeout)
+	}
+}
+
+func TestParseSbdList(t *testing.T) {
+	dataByte, err := ioutil.ReadFile(testSbdList)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataStr, err := parseSbdList(dataByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dataStr) != 3 {
+		t.Fatalf("slots: %v!=3", len(dataStr))
+	}
+
+	if dataStr[1].Node != "lustre-mds2" || dataStr[1].Message != "reset" ||
+		dataStr[1].Sender != "lustre-mds1" {
+		t.Fatalf("slot 1: %+v", dataStr[1])
+	}
+
+	if dataStr[0].Message != "clear" || dataStr[0].Sender != "" {
+		t.Fatalf("slot 0: %+v", dataStr[0])
+	}
+}
+
+func TestStonithWatchdogTimeoutValid(t *testing.T) {
+	if !stonithWatchdogTimeoutValid(10, 5) {
+		t.Fatalf("stonithWatchdogTimeoutValid(10, 5)!=true")
+	}
+
+	if stonithWatchdogTimeoutValid(5, 5) {
+		t.Fatalf("stonithWatchdogTimeoutValid(5, 5)!=false")
+	}
+
+	if !stonithWatchdogTimeoutValid(-1, 5) {
+		t.Fatalf("stonithWatchdogTimeoutValid(-1, 5)!=true")
+	}
+}
@@ -26,6 +26,7 @@ type CrmMonStruct struct {
 	NodeHistory    NodeHistoryStruct `xml:"node_history"`
 	Failures       FailuresStruct    `xml:"failures"`
 	Bans           BansStruct        `xml:"bans"`
+	Tickets        TicketsStruct     `xml:"tickets"`
 }
 
 // SummaryStruct struct stores the crm_mon XML summary information
@@ -89,20 +90,29 @@ type NodesStruct struct {
 // ResourcesStruct struct stores the crm_mon XML resources information
 type ResourcesStruct struct {
 	Resource []ResourceStruct `xml:"resource"`
-	Group    []struct {
-		ID              string           `xml:"id,attr"`
-		NumberResources float64          `xml:"number_resources,attr"`
-		Resource        []ResourceStruct `xml:"resource"`
-	} `xml:"group"`
-	Clone []struct {
-		ID             string           `xml:"id,attr"`
-		MultiState     bool             `xml:"multi_state,attr"`
-		Unique         bool             `xml:"unique,attr"`
-		Managed        bool             `xml:"managed,attr"`
-		Failed         bool             `xml:"failed,attr"`
-		FailureIgnored bool             `xml:"failure_ignored,attr"`
-		Resource       []ResourceStruct `xml:"resource"`
-	} `xml:"clone"`
+	Group    []GroupStruct    `xml:"group"`
+	Clone    []CloneStruct    `xml:"clone"`
+}
+
+// GroupStruct struct stores the crm_mon XML group information
+type GroupStruct struct {
+	ID              string           `xml:"id,attr"`
+	NumberResources float64          `xml:"number_resources,attr"`
+	Resource        []ResourceStruct `xml:"resource"`
+}
+
+// CloneStruct struct stores the crm_mon XML clone information
+type CloneStruct struct {
+	ID             string           `xml:"id,attr"`
+	MultiState     bool             `xml:"multi_state,attr"`
+	Unique         bool             `xml:"unique,attr"`
+	Managed        bool             `xml:"managed,attr"`
+	Failed         bool             `xml:"failed,attr"`
+	FailureIgnored bool             `xml:"failure_ignored,attr"`
+	TargetRole     string           `xml:"target_role,attr"`
+	Resource       []ResourceStruct `xml:"resource"`
+	// Group stores the instances of a cloned group.
+	Group []GroupStruct `xml:"group"`
 }
 
 // NodeAttrStruct struct stores the crm_mon XML node_attributes information
@@ -165,6 +175,16 @@ type BansStruct struct {
 	} `xml:"ban"`
 }
 
+// TicketsStruct struct stores the crm_mon XML tickets information
+type TicketsStruct struct {
+	Ticket []struct {
+		ID          string `xml:"id,attr"`
+		Status      string `xml:"status,attr"`
+		Standby     bool   `xml:"standby,attr"`
+		LastGranted string `xml:"last-granted,attr"`
+	} `xml:"ticket"`
+}
+
 // ResourceStruct struct stores the crm_mon XML resource information
 type ResourceStruct struct {
 	ID             string `xml:"id,attr"`
@@ -183,3 +203,169 @@ type ResourceStruct struct {
 		Cached string  `xml:"cached,attr"`
 	} `xml:"node"`
 }
+
+// CibStruct struct stores the cibadmin XML information
+type CibStruct struct {
+	XMLName       xml.Name `xml:"cib"`
+	AdminEpoch    float64  `xml:"admin_epoch,attr"`
+	Epoch         float64  `xml:"epoch,attr"`
+	NumUpdates    float64  `xml:"num_updates,attr"`
+	Configuration struct {
+		CrmConfig struct {
+			ClusterPropertySet []NVSetStruct `xml:"cluster_property_set"`
+		} `xml:"crm_config"`
+		Nodes struct {
+			Node []struct {
+				ID    string `xml:"id,attr"`
+				Uname string `xml:"uname,attr"`
+			} `xml:"node"`
+		} `xml:"nodes"`
+		Resources       CibResourcesStruct   `xml:"resources"`
+		Constraints     CibConstraintsStruct `xml:"constraints"`
+		FencingTopology struct {
+			FencingLevel []struct {
+				ID            string `xml:"id,attr"`
+				Target        string `xml:"target,attr"`
+				TargetPattern string `xml:"target-pattern,attr"`
+				Index         string `xml:"index,attr"`
+				Devices       string `xml:"devices,attr"`
+			} `xml:"fencing-level"`
+		} `xml:"fencing-topology"`
+	} `xml:"configuration"`
+}
+
+// CibResourcesStruct struct stores the CIB XML resources information
+type CibResourcesStruct struct {
+	Primitive []CibPrimitiveStruct `xml:"primitive"`
+	Group     []CibGroupStruct     `xml:"group"`
+	Clone     []CibCloneStruct     `xml:"clone"`
+	// Master is the legacy name of a promotable clone.
+	Master []CibCloneStruct `xml:"master"`
+}
+
+// CibPrimitiveStruct struct stores the CIB XML primitive information
+type CibPrimitiveStruct struct {
+	ID                 string        `xml:"id,attr"`
+	Class              string        `xml:"class,attr"`
+	Provider           string        `xml:"provider,attr"`
+	Type               string        `xml:"type,attr"`
+	InstanceAttributes []NVSetStruct `xml:"instance_attributes"`
+	MetaAttributes     []NVSetStruct `xml:"meta_attributes"`
+}
+
+// CibGroupStruct struct stores the CIB XML group information
+type CibGroupStruct struct {
+	ID             string               `xml:"id,attr"`
+	MetaAttributes []NVSetStruct        `xml:"meta_attributes"`
+	Primitive      []CibPrimitiveStruct `xml:"primitive"`
+}
+
+// CibCloneStruct struct stores the CIB XML clone information
+type CibCloneStruct struct {
+	ID             string               `xml:"id,attr"`
+	MetaAttributes []NVSetStruct        `xml:"meta_attributes"`
+	Primitive      []CibPrimitiveStruct `xml:"primitive"`
+	Group          []CibGroupStruct     `xml:"group"`
+}
+
+// CibConstraintsStruct struct stores the CIB XML constraints information
+type CibConstraintsStruct struct {
+	RscLocation   []CibLocationStruct   `xml:"rsc_location"`
+	RscColocation []CibConstraintStruct `xml:"rsc_colocation"`
+	RscOrder      []CibConstraintStruct `xml:"rsc_order"`
+	RscTicket     []CibConstraintStruct `xml:"rsc_ticket"`
+}
+
+// CibConstraintStruct struct stores the CIB XML constraint information
+type CibConstraintStruct struct {
+	ID string `xml:"id,attr"`
+}
+
+// CibLocationStruct struct stores the CIB XML location constraint information
+type CibLocationStruct struct {
+	ID         string          `xml:"id,attr"`
+	Rsc        string          `xml:"rsc,attr"`
+	RscPattern string          `xml:"rsc-pattern,attr"`
+	Role       string          `xml:"role,attr"`
+	Node       string          `xml:"node,attr"`
+	Score      string          `xml:"score,attr"`
+	Rule       []CibRuleStruct `xml:"rule"`
+}
+
+// CibRuleStruct struct stores the CIB XML rule information
+type CibRuleStruct struct {
+	ID         string `xml:"id,attr"`
+	Score      string `xml:"score,attr"`
+	BooleanOp  string `xml:"boolean-op,attr"`
+	Expression []struct {
+		ID        string `xml:"id,attr"`
+		Attribute string `xml:"attribute,attr"`
+		Operation string `xml:"operation,attr"`
+		Value     string `xml:"value,attr"`
+		Type      string `xml:"type,attr"`
+	} `xml:"expression"`
+	DateExpression []CibDateExpressionStruct `xml:"date_expression"`
+	Rule           []CibRuleStruct           `xml:"rule"`
+}
+
+// CibDateExpressionStruct struct stores the CIB XML date_expression information
+type CibDateExpressionStruct struct {
+	ID        string `xml:"id,attr"`
+	Operation string `xml:"operation,attr"`
+	Start     string `xml:"start,attr"`
+	End       string `xml:"end,attr"`
+	Duration  *struct {
+		Years   int `xml:"years,attr"`
+		Months  int `xml:"months,attr"`
+		Weeks   int `xml:"weeks,attr"`
+		Days    int `xml:"days,attr"`
+		Hours   int `xml:"hours,attr"`
+		Minutes int `xml:"minutes,attr"`
+		Seconds int `xml:"seconds,attr"`
+	} `xml:"duration"`
+}
+
+// NVSetStruct struct stores a CIB XML set of name/value pairs
+type NVSetStruct struct {
+	ID     string `xml:"id,attr"`
+	NVPair []struct {
+		ID    string `xml:"id,attr"`
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"nvpair"`
+}
+
+// StonithAdminStruct struct stores the stonith_admin XML information
+type StonithAdminStruct struct {
+	XMLName      xml.Name `xml:"pacemaker-result"`
+	FenceHistory struct {
+		FenceEvent []FenceEventStruct `xml:"fence_event"`
+	} `xml:"fence_history"`
+	List []struct {
+		Name  string   `xml:"name,attr"`
+		Count float64  `xml:"count,attr"`
+		Item  []string `xml:"item"`
+	} `xml:"list"`
+}
+
+// FenceEventStruct struct stores a fencing history event
+type FenceEventStruct struct {
+	Action     string `xml:"action,attr"`
+	Target     string `xml:"target,attr"`
+	Client     string `xml:"client,attr"`
+	Origin     string `xml:"origin,attr"`
+	Status     string `xml:"status,attr"`
+	ExitReason string `xml:"exit-reason,attr"`
+	Delegate   string `xml:"delegate,attr"`
+	Completed  string `xml:"completed,attr"`
+}
+
+// CrmVerifyStruct struct stores the crm_verify XML information
+type CrmVerifyStruct struct {
+	XMLName xml.Name `xml:"pacemaker-result"`
+	Status  struct {
+		Code    string   `xml:"code,attr"`
+		Message string   `xml:"message,attr"`
+		Errors  []string `xml:"errors>error"`
+	} `xml:"status"`
+}
@@ -17,6 +17,7 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/mjtrangoni/pacemaker_exporter/collector"
 	"github.com/prometheus/client_golang/prometheus"
@@ -87,14 +88,30 @@ func main() {
 			"Path under which to expose crm_mon html output.").Default("/html").String()
 		xmlPath = kingpin.Flag("web.xml-path",
 			"Path under which to expose crm_mon xml output.").Default("/xml").String()
+		alertAgent = kingpin.Command("alert-agent",
+			"Run as a Pacemaker alert agent, forwarding the alert to the alert collector of the running exporter.")
 		num int
 		err error
 	)
 
+	kingpin.Command("serve", "Run the exporter.").Default()
+
 	log.AddFlags(kingpin.CommandLine)
 	kingpin.Version(version.Print("pacemaker_exporter"))
 	kingpin.HelpFlag.Short('h')
-	kingpin.Parse()
+
+	command := kingpin.Parse()
+
+	// Pacemaker calls the alert agents without arguments, the alert is in
+	// the CRM_alert_* environment variables.
+	if command == alertAgent.FullCommand() || os.Getenv("CRM_alert_kind") != "" {
+		err = collector.SendAlert(os.Environ())
+		if err != nil {
+			log.Fatalf("Couldn't send alert: %s", err)
+		}
+
+		return
+	}
 
 	log.Infoln("Starting pacemaker_exporter", version.Info())
 	log.Infoln("Build context", version.BuildContext())